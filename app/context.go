@@ -9,6 +9,18 @@ import (
 	"time"
 )
 
+type shutdownTimeoutKey struct{}
+
+// ShutdownTimeout returns the graceful shutdown timeout, before the application is forcibly exited, configured on
+// the context returned by `Context().Build()`.
+//
+// This allows components such as HTTP servers to size their drain to complete before the forced exit. false is
+// returned if the context was not built by `Context()` or the timeout is disabled.
+func ShutdownTimeout(ctx context.Context) (timeout time.Duration, ok bool) {
+	timeout, ok = ctx.Value(shutdownTimeoutKey{}).(time.Duration)
+	return timeout, ok && timeout > 0
+}
+
 type contextBuilder struct {
 	signals   []os.Signal
 	timeout   time.Duration
@@ -42,6 +54,9 @@ func (c *contextBuilder) Signals(signals ...os.Signal) *contextBuilder {
 //
 // A timeout of <= 0, not recommended, disables the timeout and will wait forever for a seconds signal or application
 // shuts down.
+//
+// The timeout is available from the built context via `ShutdownTimeout` allowing graceful shutdowns, such as
+// `httpext.ServerRunner`, to complete before being forced.
 func (c *contextBuilder) Timeout(timeout time.Duration) *contextBuilder {
 	c.timeout = timeout
 	return c
@@ -69,7 +84,7 @@ func (c *contextBuilder) Build() context.Context {
 	var sig = make(chan os.Signal, 1)
 	signal.Notify(sig, c.signals...)
 
-	ctx, cancel := context.WithCancel(context.Background())
+	ctx, cancel := context.WithCancel(context.WithValue(context.Background(), shutdownTimeoutKey{}, c.timeout))
 
 	go listen(sig, cancel, c.exitFn, c.timeout, c.forceExit)
 
@@ -81,3 +81,16 @@ func TestTimeoutWithNoForceExit(t *testing.T) {
 	wg.Wait()
 	Equal(t, context.Canceled, ctx.Err())
 }
+
+func TestShutdownTimeout(t *testing.T) {
+	timeout, ok := ShutdownTimeout(context.Background())
+	Equal(t, ok, false)
+	Equal(t, timeout, time.Duration(0))
+
+	timeout, ok = ShutdownTimeout(Context().Timeout(time.Minute).Build())
+	Equal(t, ok, true)
+	Equal(t, timeout, time.Minute)
+
+	_, ok = ShutdownTimeout(Context().Timeout(0).Build())
+	Equal(t, ok, false)
+}
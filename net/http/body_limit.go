@@ -0,0 +1,127 @@
+package httpext
+
+import (
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	bytesext "github.com/go-playground/pkg/v5/bytes"
+	ioext "github.com/go-playground/pkg/v5/io"
+)
+
+// BodyLimit is a middleware which limits the size of request bodies, responding with 413 Request Entity Too Large
+// and an application/problem+json body when exceeded.
+//
+// A base `BodyLimit` can be changed for individual routes eg. raising the limit for uploads.
+type BodyLimit struct {
+	limit        bytesext.Bytes
+	contentTypes map[string]bytesext.Bytes
+}
+
+// NewBodyLimit returns a new `BodyLimit` limiting all request bodies to the provided limit. A negative limit disables
+// limiting.
+func NewBodyLimit(limit bytesext.Bytes) BodyLimit {
+	return BodyLimit{limit: limit}
+}
+
+// Limit sets the default limit used when no content type specific limit matches. A negative limit disables
+// limiting.
+func (b BodyLimit) Limit(limit bytesext.Bytes) BodyLimit {
+	b.limit = limit
+	return b
+}
+
+// ContentType sets the limit for requests with the provided media type, which may contain a wildcard subtype
+// eg. "image/*". An exact match takes precedence over a wildcard.
+func (b BodyLimit) ContentType(mediaType string, limit bytesext.Bytes) BodyLimit {
+	contentTypes := make(map[string]bytesext.Bytes, len(b.contentTypes)+1)
+	for k, v := range b.contentTypes {
+		contentTypes[k] = v
+	}
+	contentTypes[strings.ToLower(mediaType)] = limit
+	b.contentTypes = contentTypes
+	return b
+}
+
+func (b BodyLimit) limitFor(r *http.Request) bytesext.Bytes {
+	if len(b.contentTypes) > 0 {
+		if typ, _, err := mime.ParseMediaType(r.Header.Get(ContentType)); err == nil {
+			if limit, ok := b.contentTypes[typ]; ok {
+				return limit
+			}
+			if idx := strings.IndexByte(typ, '/'); idx != -1 {
+				if limit, ok := b.contentTypes[typ[:idx]+"/*"]; ok {
+					return limit
+				}
+			}
+		}
+	}
+	return b.limit
+}
+
+// Handler returns an http.Handler which limits the request body before calling next.
+//
+// Requests with a Content-Length greater than the limit are rejected without calling next. Otherwise the request body
+// is wrapped with `ioext.LimitReader` semantics, returning `ioext.ErrLimitedReaderEOF` once the limit is exceeded, at
+// which point the 413 response is written if the response headers have not yet been sent and any subsequent writes
+// by next are discarded.
+func (b BodyLimit) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		limit := b.limitFor(r)
+		if limit < 0 || r.Body == nil || r.Body == http.NoBody {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if r.ContentLength > limit {
+			writeBodyTooLarge(w, limit)
+			return
+		}
+		lw := &responseWriter{ResponseWriter: w}
+		r.Body = &limitedBody{
+			LimitedReader: ioext.LimitReader(r.Body, limit),
+			closer:        r.Body,
+			w:             lw,
+			limit:         limit,
+		}
+		next.ServeHTTP(lw, r)
+	})
+}
+
+func writeBodyTooLarge(w http.ResponseWriter, limit bytesext.Bytes) {
+	w.Header().Set(Connection, "close")
+	_ = ProblemJSON(w, ProblemDetails{
+		Status: http.StatusRequestEntityTooLarge,
+		Detail: "request body exceeds the limit of " + strconv.FormatInt(limit, 10) + " bytes",
+	})
+}
+
+type limitedBody struct {
+	*ioext.LimitedReader
+	closer   io.Closer
+	w        *responseWriter
+	limit    bytesext.Bytes
+	exceeded bool
+}
+
+func (l *limitedBody) Read(p []byte) (n int, err error) {
+	if l.exceeded {
+		return 0, ioext.ErrLimitedReaderEOF
+	}
+	n, err = l.LimitedReader.Read(p)
+	if l.N < 0 {
+		// the LimitedReader reads a byte past the limit to detect it being exceeded which must not be returned
+		n, err, l.exceeded = n+int(l.N), ioext.ErrLimitedReaderEOF, true
+		if !l.w.wroteHeader {
+			// discard any further writes by the handler once the 413 response has been written
+			writeBodyTooLarge(l.w.ResponseWriter, l.limit)
+			l.w.wroteHeader, l.w.writeErr = true, ioext.ErrLimitedReaderEOF
+		}
+	}
+	return
+}
+
+func (l *limitedBody) Close() error {
+	return l.closer.Close()
+}
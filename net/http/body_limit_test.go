@@ -0,0 +1,116 @@
+package httpext
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/go-playground/assert/v2"
+	ioext "github.com/go-playground/pkg/v5/io"
+)
+
+func TestBodyLimit(t *testing.T) {
+	var (
+		readErr error
+		read    []byte
+	)
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		read, readErr = io.ReadAll(r.Body)
+		if readErr != nil {
+			// attempt to write after the limit was hit which must be discarded
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	limit := NewBodyLimit(10).
+		ContentType(ApplicationJSONNoCharset, 20).
+		ContentType("image/*", -1)
+
+	tests := []struct {
+		name           string
+		contentType    string
+		body           string
+		unknownLength  bool
+		expectedStatus int
+		expectedErr    error
+		expectedRead   int
+	}{
+		{
+			name:           "under limit",
+			body:           "0123456789",
+			expectedStatus: http.StatusOK,
+			expectedRead:   10,
+		},
+		{
+			name:           "content length over limit",
+			body:           "0123456789a",
+			expectedStatus: http.StatusRequestEntityTooLarge,
+		},
+		{
+			name:           "mid-stream over limit",
+			body:           "0123456789a",
+			unknownLength:  true,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedErr:    ioext.ErrLimitedReaderEOF,
+			expectedRead:   10,
+		},
+		{
+			name:           "content type limit",
+			contentType:    ApplicationJSON,
+			body:           strings.Repeat("a", 20),
+			expectedStatus: http.StatusOK,
+			expectedRead:   20,
+		},
+		{
+			name:           "content type limit exceeded",
+			contentType:    ApplicationJSON,
+			body:           strings.Repeat("a", 21),
+			unknownLength:  true,
+			expectedStatus: http.StatusRequestEntityTooLarge,
+			expectedErr:    ioext.ErrLimitedReaderEOF,
+			expectedRead:   20,
+		},
+		{
+			name:           "wildcard content type unlimited",
+			contentType:    ImagePNG,
+			body:           strings.Repeat("a", 100),
+			expectedStatus: http.StatusOK,
+			expectedRead:   100,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			readErr, read = nil, nil
+			var body io.Reader = strings.NewReader(tc.body)
+			if tc.unknownLength {
+				body = io.MultiReader(body)
+			}
+			req := httptest.NewRequest(http.MethodPost, "/", body)
+			if tc.unknownLength {
+				req.ContentLength = -1
+			}
+			if tc.contentType != "" {
+				req.Header.Set(ContentType, tc.contentType)
+			}
+			w := httptest.NewRecorder()
+			limit.Handler(next).ServeHTTP(w, req)
+
+			Equal(t, w.Code, tc.expectedStatus)
+			Equal(t, readErr, tc.expectedErr)
+			Equal(t, len(read), tc.expectedRead)
+			if tc.expectedStatus == http.StatusRequestEntityTooLarge {
+				Equal(t, w.Header().Get(ContentType), ApplicationProblemJSON)
+				var problem ProblemDetails
+				Equal(t, json.Unmarshal(w.Body.Bytes(), &problem), nil)
+				Equal(t, problem.Status, http.StatusRequestEntityTooLarge)
+				Equal(t, problem.Title, "Request Entity Too Large")
+			}
+		})
+	}
+}
@@ -0,0 +1,312 @@
+//go:build go1.18
+// +build go1.18
+
+package httpext
+
+import (
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	asciiext "github.com/go-playground/pkg/v5/ascii"
+	. "github.com/go-playground/pkg/v5/values/option"
+)
+
+// maxDeltaSeconds is the greatest delta-seconds value that is required to be handled as described by
+// RFC 9111 section 1.2.2, any larger values are capped to it.
+const maxDeltaSeconds = 1<<31 - 1
+
+// CacheControlDirectives represents the parsed request and response directives of the Cache-Control header as
+// described by RFC 9111 section 5.2, along with the common RFC 5861 and RFC 8246 extensions.
+//
+// Directives not applicable to the context, request vs response, may be ignored by the caller.
+type CacheControlDirectives struct {
+	// MaxAge is the max-age directive.
+	MaxAge Option[time.Duration]
+
+	// SMaxAge is the s-maxage response directive.
+	SMaxAge Option[time.Duration]
+
+	// MaxStale is the max-stale request directive with a value, see also MaxStaleAny.
+	MaxStale Option[time.Duration]
+
+	// MaxStaleAny is the max-stale request directive without a value, indicating any staleness is acceptable.
+	MaxStaleAny bool
+
+	// MinFresh is the min-fresh request directive.
+	MinFresh Option[time.Duration]
+
+	// StaleWhileRevalidate is the stale-while-revalidate response directive.
+	StaleWhileRevalidate Option[time.Duration]
+
+	// StaleIfError is the stale-if-error directive.
+	StaleIfError Option[time.Duration]
+
+	// NoCache is the no-cache directive.
+	NoCache bool
+
+	// NoStore is the no-store directive.
+	NoStore bool
+
+	// NoTransform is the no-transform directive.
+	NoTransform bool
+
+	// OnlyIfCached is the only-if-cached request directive.
+	OnlyIfCached bool
+
+	// MustRevalidate is the must-revalidate response directive.
+	MustRevalidate bool
+
+	// ProxyRevalidate is the proxy-revalidate response directive.
+	ProxyRevalidate bool
+
+	// MustUnderstand is the must-understand response directive.
+	MustUnderstand bool
+
+	// Private is the private response directive.
+	Private bool
+
+	// Public is the public response directive.
+	Public bool
+
+	// Immutable is the immutable response directive.
+	Immutable bool
+
+	// Extensions contains any unrecognised directives keyed by their lower-cased name, with an empty value when the
+	// directive has no argument.
+	Extensions map[string]string
+}
+
+// ParseCacheControl parses all Cache-Control headers into their directives.
+//
+// Directives with invalid arguments are ignored and, as described by RFC 9111 section 4.2.1, only the first
+// occurrence of a duplicate directive is used.
+func ParseCacheControl(headers http.Header) (cc CacheControlDirectives) {
+	seen := make(map[string]bool)
+	for _, value := range headers.Values(CacheControl) {
+		for value != "" {
+			var directive string
+			directive, value = nextCacheDirective(value)
+			if directive == "" {
+				continue
+			}
+			name, arg := directive, ""
+			if idx := strings.IndexByte(directive, '='); idx != -1 {
+				name, arg = strings.TrimSpace(directive[:idx]), strings.TrimSpace(directive[idx+1:])
+				if len(arg) > 1 && arg[0] == '"' && arg[len(arg)-1] == '"' {
+					arg = arg[1 : len(arg)-1]
+				}
+			}
+			name = strings.ToLower(name)
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			cc.set(name, arg)
+		}
+	}
+	return
+}
+
+// nextCacheDirective returns the next comma separated directive, respecting quoted-strings, and the remainder.
+func nextCacheDirective(s string) (directive, remainder string) {
+	var quoted bool
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '"':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				return strings.TrimSpace(s[:i]), s[i+1:]
+			}
+		}
+	}
+	return strings.TrimSpace(s), ""
+}
+
+func (cc *CacheControlDirectives) set(name, arg string) {
+	switch name {
+	case "max-age":
+		cc.MaxAge = parseDeltaSeconds(arg)
+	case "s-maxage":
+		cc.SMaxAge = parseDeltaSeconds(arg)
+	case "max-stale":
+		if arg == "" {
+			cc.MaxStaleAny = true
+		} else {
+			cc.MaxStale = parseDeltaSeconds(arg)
+		}
+	case "min-fresh":
+		cc.MinFresh = parseDeltaSeconds(arg)
+	case "stale-while-revalidate":
+		cc.StaleWhileRevalidate = parseDeltaSeconds(arg)
+	case "stale-if-error":
+		cc.StaleIfError = parseDeltaSeconds(arg)
+	case "no-cache":
+		cc.NoCache = true
+	case "no-store":
+		cc.NoStore = true
+	case "no-transform":
+		cc.NoTransform = true
+	case "only-if-cached":
+		cc.OnlyIfCached = true
+	case "must-revalidate":
+		cc.MustRevalidate = true
+	case "proxy-revalidate":
+		cc.ProxyRevalidate = true
+	case "must-understand":
+		cc.MustUnderstand = true
+	case "private":
+		cc.Private = true
+	case "public":
+		cc.Public = true
+	case "immutable":
+		cc.Immutable = true
+	default:
+		if cc.Extensions == nil {
+			cc.Extensions = make(map[string]string)
+		}
+		cc.Extensions[name] = arg
+	}
+}
+
+func parseDeltaSeconds(s string) Option[time.Duration] {
+	if s == "" {
+		return None[time.Duration]()
+	}
+	for i := 0; i < len(s); i++ {
+		if !asciiext.IsDigit(s[i]) {
+			return None[time.Duration]()
+		}
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n > maxDeltaSeconds {
+		n = maxDeltaSeconds
+	}
+	return Some(time.Duration(n) * time.Second)
+}
+
+// String returns the directives formatted as a Cache-Control header value.
+func (cc CacheControlDirectives) String() string {
+	var directives []string
+	flag := func(set bool, name string) {
+		if set {
+			directives = append(directives, name)
+		}
+	}
+	delta := func(o Option[time.Duration], name string) {
+		if o.IsSome() {
+			directives = append(directives, name+"="+strconv.FormatInt(int64(o.Unwrap()/time.Second), 10))
+		}
+	}
+	flag(cc.Public, "public")
+	flag(cc.Private, "private")
+	flag(cc.NoCache, "no-cache")
+	flag(cc.NoStore, "no-store")
+	flag(cc.NoTransform, "no-transform")
+	flag(cc.OnlyIfCached, "only-if-cached")
+	flag(cc.MustRevalidate, "must-revalidate")
+	flag(cc.ProxyRevalidate, "proxy-revalidate")
+	flag(cc.MustUnderstand, "must-understand")
+	flag(cc.Immutable, "immutable")
+	delta(cc.MaxAge, "max-age")
+	delta(cc.SMaxAge, "s-maxage")
+	flag(cc.MaxStaleAny, "max-stale")
+	if !cc.MaxStaleAny {
+		delta(cc.MaxStale, "max-stale")
+	}
+	delta(cc.MinFresh, "min-fresh")
+	delta(cc.StaleWhileRevalidate, "stale-while-revalidate")
+	delta(cc.StaleIfError, "stale-if-error")
+
+	keys := make([]string, 0, len(cc.Extensions))
+	for k := range cc.Extensions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		if v := cc.Extensions[k]; v != "" {
+			if strings.ContainsAny(v, " \t,;=\"") {
+				v = strconv.Quote(v)
+			}
+			directives = append(directives, k+"="+v)
+		} else {
+			directives = append(directives, k)
+		}
+	}
+	return strings.Join(directives, ", ")
+}
+
+// Freshness represents the freshness of a stored response as described by RFC 9111 section 4.2.
+type Freshness struct {
+	// Lifetime is the freshness lifetime of the response.
+	Lifetime time.Duration
+
+	// Age is the current age of the response.
+	Age time.Duration
+
+	// Heuristic indicates the Lifetime was heuristically calculated from the Last-Modified header as no explicit
+	// expiration time was present.
+	Heuristic bool
+}
+
+// IsFresh returns true if the response is still fresh.
+func (f Freshness) IsFresh() bool {
+	return f.Lifetime > f.Age
+}
+
+// Remaining returns the remaining time to live of the response before it becomes stale, which is negative once stale.
+func (f Freshness) Remaining() time.Duration {
+	return f.Lifetime - f.Age
+}
+
+// CalculateFreshness calculates the freshness of a response, from its headers, using the Cache-Control, Expires,
+// Date, Age and Last-Modified headers as described by RFC 9111 section 4.2.
+//
+// requestTime is when the request was sent, responseTime when the response was received and now the current time.
+// When calculating for a response generated locally all three may be the same. shared indicates if the calculation
+// is for a shared cache, in which case s-maxage takes precedence.
+func CalculateFreshness(headers http.Header, requestTime, responseTime, now time.Time, shared bool) (f Freshness) {
+	cc := ParseCacheControl(headers)
+
+	dateValue := responseTime
+	if t, err := http.ParseTime(headers.Get(Date)); err == nil {
+		dateValue = t
+	}
+
+	switch {
+	case shared && cc.SMaxAge.IsSome():
+		f.Lifetime = cc.SMaxAge.Unwrap()
+	case cc.MaxAge.IsSome():
+		f.Lifetime = cc.MaxAge.Unwrap()
+	case headers.Get(Expires) != "":
+		// an invalid Expires, eg. "0", represents a time in the past
+		if t, err := http.ParseTime(headers.Get(Expires)); err == nil && t.After(dateValue) {
+			f.Lifetime = t.Sub(dateValue)
+		}
+	default:
+		if t, err := http.ParseTime(headers.Get(LastModified)); err == nil && dateValue.After(t) {
+			f.Lifetime = dateValue.Sub(t) / 10
+			f.Heuristic = true
+		}
+	}
+
+	// RFC 9111 section 4.2.3 age calculation
+	var ageValue time.Duration
+	if age := parseDeltaSeconds(strings.TrimSpace(headers.Get(Age))); age.IsSome() {
+		ageValue = age.Unwrap()
+	}
+	apparentAge := responseTime.Sub(dateValue)
+	if apparentAge < 0 {
+		apparentAge = 0
+	}
+	correctedAgeValue := ageValue + responseTime.Sub(requestTime)
+	correctedInitialAge := apparentAge
+	if correctedAgeValue > correctedInitialAge {
+		correctedInitialAge = correctedAgeValue
+	}
+	f.Age = correctedInitialAge + now.Sub(responseTime)
+	return
+}
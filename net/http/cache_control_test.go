@@ -0,0 +1,121 @@
+//go:build go1.18
+// +build go1.18
+
+package httpext
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/go-playground/assert/v2"
+	. "github.com/go-playground/pkg/v5/values/option"
+)
+
+func TestParseCacheControl(t *testing.T) {
+	headers := make(http.Header)
+	headers.Add(CacheControl, `public, max-age=60, s-maxage="120", stale-while-revalidate=30`)
+	headers.Add(CacheControl, `MAX-AGE=10, no-cache="Set-Cookie, Foo", max-stale, min-fresh=invalid, community="UCI", immutable`)
+
+	cc := ParseCacheControl(headers)
+	Equal(t, cc.Public, true)
+	Equal(t, cc.MaxAge, Some(60*time.Second))
+	Equal(t, cc.SMaxAge, Some(120*time.Second))
+	Equal(t, cc.StaleWhileRevalidate, Some(30*time.Second))
+	Equal(t, cc.NoCache, true)
+	Equal(t, cc.MaxStaleAny, true)
+	Equal(t, cc.MinFresh.IsNone(), true)
+	Equal(t, cc.Immutable, true)
+	Equal(t, cc.Private, false)
+	Equal(t, cc.Extensions, map[string]string{"community": "UCI"})
+
+	headers.Set(CacheControl, "max-age=99999999999999")
+	Equal(t, ParseCacheControl(headers).MaxAge, Some(time.Duration(maxDeltaSeconds)*time.Second))
+}
+
+func TestCacheControlString(t *testing.T) {
+	cc := CacheControlDirectives{
+		Private:              true,
+		NoStore:              true,
+		MaxAge:               Some(time.Minute),
+		StaleIfError:         Some(time.Hour),
+		MaxStale:             Some(time.Second),
+		Extensions:           map[string]string{"community": "a b", "ext": ""},
+		StaleWhileRevalidate: None[time.Duration](),
+	}
+	value := cc.String()
+	Equal(t, value, `private, no-store, max-age=60, max-stale=1, stale-if-error=3600, community="a b", ext`)
+	Equal(t, ParseCacheControl(http.Header{CacheControl: []string{value}}), cc)
+}
+
+func TestCalculateFreshness(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name         string
+		headers      map[string]string
+		requestTime  time.Time
+		responseTime time.Time
+		shared       bool
+		expected     Freshness
+	}{
+		{
+			name:         "max-age",
+			headers:      map[string]string{CacheControl: "max-age=60, s-maxage=120", Date: now.Format(http.TimeFormat)},
+			requestTime:  now,
+			responseTime: now,
+			expected:     Freshness{Lifetime: time.Minute},
+		},
+		{
+			name:         "s-maxage-shared",
+			headers:      map[string]string{CacheControl: "max-age=60, s-maxage=120", Date: now.Format(http.TimeFormat)},
+			requestTime:  now,
+			responseTime: now,
+			shared:       true,
+			expected:     Freshness{Lifetime: 2 * time.Minute},
+		},
+		{
+			name:         "expires-with-age",
+			headers:      map[string]string{Expires: now.Add(time.Hour).Format(http.TimeFormat), Date: now.Format(http.TimeFormat), Age: "30"},
+			requestTime:  now.Add(-2 * time.Second),
+			responseTime: now,
+			expected:     Freshness{Lifetime: time.Hour, Age: 32 * time.Second},
+		},
+		{
+			name:         "invalid-expires",
+			headers:      map[string]string{Expires: "0", Date: now.Format(http.TimeFormat)},
+			requestTime:  now,
+			responseTime: now,
+			expected:     Freshness{},
+		},
+		{
+			name:         "apparent-age",
+			headers:      map[string]string{CacheControl: "max-age=60", Date: now.Add(-10 * time.Second).Format(http.TimeFormat)},
+			requestTime:  now,
+			responseTime: now,
+			expected:     Freshness{Lifetime: time.Minute, Age: 10 * time.Second},
+		},
+		{
+			name:         "heuristic",
+			headers:      map[string]string{LastModified: now.Add(-100 * time.Hour).Format(http.TimeFormat), Date: now.Format(http.TimeFormat)},
+			requestTime:  now,
+			responseTime: now,
+			expected:     Freshness{Lifetime: 10 * time.Hour, Heuristic: true},
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			headers := make(http.Header)
+			for k, v := range tt.headers {
+				headers.Set(k, v)
+			}
+			f := CalculateFreshness(headers, tt.requestTime, tt.responseTime, now, tt.shared)
+			Equal(t, f, tt.expected)
+		})
+	}
+
+	f := CalculateFreshness(http.Header{CacheControl: []string{"max-age=60"}}, now, now, now.Add(90*time.Second), false)
+	Equal(t, f.IsFresh(), false)
+	Equal(t, f.Remaining(), -30*time.Second)
+}
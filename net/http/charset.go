@@ -1,7 +1,127 @@
 package httpext
 
+import (
+	"errors"
+	"io"
+	"mime"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
 // Charset values
 const (
 	UTF8     string = "utf-8"
 	ISO88591 string = "iso-8859-1"
 )
+
+// ErrUnsupportedCharset is returned by `CharsetReader` when the charset has no registered `CharsetReaderFn`.
+var ErrUnsupportedCharset = errors.New("unsupported charset")
+
+// CharsetReaderFn returns a reader which transcodes the input from a charset to UTF-8.
+type CharsetReaderFn func(input io.Reader) io.Reader
+
+var (
+	charsetsMu sync.RWMutex
+	charsets   = map[string]CharsetReaderFn{
+		ISO88591:     NewLatin1Reader,
+		"iso8859-1":  NewLatin1Reader,
+		"iso_8859-1": NewLatin1Reader,
+		"latin1":     NewLatin1Reader,
+		"l1":         NewLatin1Reader,
+		"cp819":      NewLatin1Reader,
+		"ibm819":     NewLatin1Reader,
+		// US-ASCII is a subset of both ISO-8859-1 and UTF-8
+		"us-ascii": NewLatin1Reader,
+	}
+)
+
+// RegisterCharset registers the function used to transcode the named charset, matched case-insensitively, to UTF-8
+// when decoding JSON, XML, CSV and text bodies, replacing any previously registered for the name.
+//
+// ISO-8859-1, and its common aliases, are registered by default. Bodies with an unregistered Content-Type charset are
+// decoded as is.
+func RegisterCharset(name string, fn CharsetReaderFn) {
+	charsetsMu.Lock()
+	charsets[strings.ToLower(name)] = fn
+	charsetsMu.Unlock()
+}
+
+// CharsetReader returns a reader which transcodes the input from the named charset to UTF-8 using the registered
+// `CharsetReaderFn`s, returning the input as is for UTF-8, and is suitable for use as the xml.Decoder CharsetReader.
+//
+// ErrUnsupportedCharset is returned if no `CharsetReaderFn` is registered for the charset.
+func CharsetReader(charset string, input io.Reader) (io.Reader, error) {
+	charset = strings.ToLower(strings.TrimSpace(charset))
+	if charset == "" || charset == UTF8 || charset == "utf8" {
+		return input, nil
+	}
+	charsetsMu.RLock()
+	fn, ok := charsets[charset]
+	charsetsMu.RUnlock()
+	if !ok {
+		return nil, ErrUnsupportedCharset
+	}
+	return fn(input), nil
+}
+
+// contentTypeReader returns a reader which transcodes the input from the Content-Type charset to UTF-8, passing the
+// input through as is when the charset is unsupported so bodies, such as JSON which defines no charset, mislabeled
+// with an unknown charset still decode.
+func contentTypeReader(contentType string, input io.Reader) io.Reader {
+	if reader, err := CharsetReader(contentTypeCharset(contentType), input); err == nil {
+		return reader
+	}
+	return input
+}
+
+// contentTypeCharset returns the charset parameter of the Content-Type, if any.
+func contentTypeCharset(contentType string) string {
+	if contentType == "" {
+		return ""
+	}
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return params["charset"]
+}
+
+// NewLatin1Reader returns a reader which transcodes the ISO-8859-1, Latin-1, input to UTF-8.
+func NewLatin1Reader(input io.Reader) io.Reader {
+	return &latin1Reader{r: input}
+}
+
+type latin1Reader struct {
+	r   io.Reader
+	raw []byte
+	buf []byte
+	err error
+}
+
+func (l *latin1Reader) Read(p []byte) (int, error) {
+	for len(l.buf) == 0 {
+		if l.err != nil {
+			return 0, l.err
+		}
+		if len(p) == 0 {
+			return 0, nil
+		}
+		if cap(l.raw) < len(p) {
+			l.raw = make([]byte, len(p))
+		}
+		var n int
+		n, l.err = l.r.Read(l.raw[:len(p)])
+		l.buf = l.buf[:0]
+		for _, c := range l.raw[:n] {
+			if c < utf8.RuneSelf {
+				l.buf = append(l.buf, c)
+			} else {
+				l.buf = append(l.buf, 0xc0|c>>6, 0x80|c&0x3f)
+			}
+		}
+	}
+	n := copy(p, l.buf)
+	l.buf = l.buf[n:]
+	return n, nil
+}
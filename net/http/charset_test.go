@@ -0,0 +1,111 @@
+package httpext
+
+import (
+	"bytes"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	. "github.com/go-playground/assert/v2"
+)
+
+func TestLatin1Reader(t *testing.T) {
+	b, err := io.ReadAll(NewLatin1Reader(bytes.NewReader([]byte("caf\xe9 \xff"))))
+	Equal(t, err, nil)
+	Equal(t, string(b), "café ÿ")
+
+	// reads smaller than the transcoded output
+	b, err = io.ReadAll(iotest.OneByteReader(NewLatin1Reader(iotest.HalfReader(bytes.NewReader([]byte("\xe9\xe9abc"))))))
+	Equal(t, err, nil)
+	Equal(t, string(b), "ééabc")
+}
+
+func TestCharsetReader(t *testing.T) {
+	r, err := CharsetReader("UTF-8", strings.NewReader("a"))
+	Equal(t, err, nil)
+	b, _ := io.ReadAll(r)
+	Equal(t, string(b), "a")
+
+	_, err = CharsetReader("x-unknown", strings.NewReader("a"))
+	Equal(t, err, ErrUnsupportedCharset)
+
+	RegisterCharset("X-Upper", func(input io.Reader) io.Reader {
+		b, _ := io.ReadAll(input)
+		return bytes.NewReader(bytes.ToUpper(b))
+	})
+	r, err = CharsetReader("x-upper", strings.NewReader("abc"))
+	Equal(t, err, nil)
+	b, _ = io.ReadAll(r)
+	Equal(t, string(b), "ABC")
+}
+
+func TestDecodeResponseAnyCharset(t *testing.T) {
+	type Test struct {
+		XMLName xml.Name `json:"-" xml:"test"`
+		Name    string   `json:"name" xml:"name"`
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		body        string
+		expected    string
+		expectedErr error
+	}{
+		{
+			name:        "json latin1",
+			contentType: ApplicationJSONNoCharset + "; charset=ISO-8859-1",
+			body:        "{\"name\":\"caf\xe9\"}",
+			expected:    "café",
+		},
+		{
+			name:        "xml declared latin1",
+			contentType: ApplicationXMLNoCharset,
+			body:        "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?><test><name>caf\xe9</name></test>",
+			expected:    "café",
+		},
+		{
+			name:        "xml header charset takes precedence",
+			contentType: ApplicationXMLNoCharset + "; charset=latin1",
+			body:        "<?xml version=\"1.0\" encoding=\"windows-1252\"?><test><name>caf\xe9</name></test>",
+			expected:    "café",
+		},
+		{
+			name:        "unsupported charset passed through",
+			contentType: ApplicationJSONNoCharset + "; charset=windows-1252",
+			body:        `{"name":"cafe"}`,
+			expected:    "cafe",
+		},
+		{
+			name:        "xml unsupported header charset falls back to declared",
+			contentType: ApplicationXMLNoCharset + "; charset=x-unknown",
+			body:        "<?xml version=\"1.0\" encoding=\"ISO-8859-1\"?><test><name>caf\xe9</name></test>",
+			expected:    "café",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			resp := &http.Response{
+				Header: http.Header{ContentType: []string{tc.contentType}},
+				Body:   io.NopCloser(strings.NewReader(tc.body)),
+			}
+			var v Test
+			err := DecodeResponseAny(resp, 1024, &v)
+			Equal(t, err, tc.expectedErr)
+			Equal(t, v.Name, tc.expected)
+		})
+	}
+
+	resp := &http.Response{
+		Header: http.Header{ContentType: []string{TextPlainNoCharset + "; charset=iso-8859-1"}},
+		Body:   io.NopCloser(strings.NewReader("caf\xe9")),
+	}
+	var s string
+	Equal(t, DecodeResponseAny(resp, 1024, &s), nil)
+	Equal(t, s, "café")
+}
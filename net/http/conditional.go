@@ -0,0 +1,215 @@
+package httpext
+
+import (
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/xml"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ETagOption represents the strength of a generated ETag.
+type ETagOption uint8
+
+// ETagOption's
+const (
+	StrongETag ETagOption = iota
+	WeakETag
+)
+
+const weakETagPrefix = "W/"
+
+// GenerateETag returns a quoted ETag, computed from the provided bytes, suitable for use as the ETag header value.
+//
+// A strong ETag should be used when the bytes are exactly what is sent to the client, a weak ETag when they are only
+// semantically equivalent eg. when the response may be further compressed or transformed.
+func GenerateETag(b []byte, opt ETagOption) string {
+	sum := sha256.Sum256(b)
+	tag := `"` + base64.RawURLEncoding.EncodeToString(sum[:16]) + `"`
+	if opt == WeakETag {
+		return weakETagPrefix + tag
+	}
+	return tag
+}
+
+// ParseETags parses an If-Match or If-None-Match header value into its individual entity-tags, retaining any weak
+// indicator and quotes, as described by RFC 9110 section 8.8.3.
+//
+// A value of "*" is returned as a single "*" element.
+func ParseETags(s string) (tags []string) {
+	for {
+		s = strings.TrimLeft(s, " \t,")
+		if s == "" {
+			return
+		}
+		if s[0] == '*' {
+			tags = append(tags, Any)
+			s = s[1:]
+			continue
+		}
+		start := s
+		if strings.HasPrefix(s, weakETagPrefix) {
+			s = s[len(weakETagPrefix):]
+		}
+		if s == "" || s[0] != '"' {
+			// invalid entity-tag, skip to next list element
+			if idx := strings.IndexByte(s, ','); idx != -1 {
+				s = s[idx:]
+				continue
+			}
+			return
+		}
+		idx := strings.IndexByte(s[1:], '"')
+		if idx == -1 {
+			return
+		}
+		end := len(start) - len(s) + idx + 2
+		tags = append(tags, start[:end])
+		s = start[end:]
+	}
+}
+
+// ETagStrongMatch returns true if both entity-tags are strong and their opaque tags are identical, as described by
+// RFC 9110 section 8.8.3.2.
+func ETagStrongMatch(a, b string) bool {
+	return !strings.HasPrefix(a, weakETagPrefix) && !strings.HasPrefix(b, weakETagPrefix) && a == b
+}
+
+// ETagWeakMatch returns true if both entity-tags opaque tags are identical regardless of either or both being
+// weak, as described by RFC 9110 section 8.8.3.2.
+func ETagWeakMatch(a, b string) bool {
+	return strings.TrimPrefix(a, weakETagPrefix) == strings.TrimPrefix(b, weakETagPrefix)
+}
+
+func etagListMatch(header, etag string, strong bool) bool {
+	for _, tag := range ParseETags(header) {
+		if tag == Any {
+			return etag != ""
+		}
+		if strong {
+			if ETagStrongMatch(tag, etag) {
+				return true
+			}
+		} else if ETagWeakMatch(tag, etag) {
+			return true
+		}
+	}
+	return false
+}
+
+// EvaluatePreconditions evaluates the conditional request headers If-Match, If-Unmodified-Since, If-None-Match and
+// If-Modified-Since, in the order described by RFC 9110 section 13.2.2, against the current representations ETag and
+// last modified time.
+//
+// It returns 0 when the request should proceed normally, http.StatusNotModified or http.StatusPreconditionFailed
+// otherwise. An empty etag or zero lastModified indicates the value is not known for the current representation.
+func EvaluatePreconditions(r *http.Request, etag string, lastModified time.Time) int {
+	if im := r.Header.Get(IfMatch); im != "" {
+		if !etagListMatch(im, etag, true) {
+			return http.StatusPreconditionFailed
+		}
+	} else if ius := r.Header.Get(IfUnmodifiedSince); ius != "" && !lastModified.IsZero() {
+		if t, err := http.ParseTime(ius); err == nil && lastModified.Truncate(time.Second).After(t) {
+			return http.StatusPreconditionFailed
+		}
+	}
+
+	isGetOrHead := r.Method == http.MethodGet || r.Method == http.MethodHead
+
+	if inm := r.Header.Get(IfNoneMatch); inm != "" {
+		if etagListMatch(inm, etag, false) {
+			if isGetOrHead {
+				return http.StatusNotModified
+			}
+			return http.StatusPreconditionFailed
+		}
+	} else if ims := r.Header.Get(IfModifiedSince); ims != "" && isGetOrHead && !lastModified.IsZero() {
+		if t, err := http.ParseTime(ims); err == nil && !lastModified.Truncate(time.Second).After(t) {
+			return http.StatusNotModified
+		}
+	}
+	return 0
+}
+
+// CheckPreconditions evaluates the conditional request headers using EvaluatePreconditions and, if the request
+// should not proceed, writes the 304 Not Modified or 412 Precondition Failed response.
+//
+// It returns true if a response was written and the handler should return without further processing. This is
+// useful for optimistic-concurrency when modifying a resource eg. PUT with If-Match.
+func CheckPreconditions(w http.ResponseWriter, r *http.Request, etag string, lastModified time.Time) (handled bool) {
+	status := EvaluatePreconditions(r, etag, lastModified)
+	switch status {
+	case http.StatusNotModified:
+		writeNotModified(w, etag, lastModified)
+		return true
+	case http.StatusPreconditionFailed:
+		w.WriteHeader(status)
+		return true
+	}
+	return false
+}
+
+func writeNotModified(w http.ResponseWriter, etag string, lastModified time.Time) {
+	h := w.Header()
+	delete(h, ContentType)
+	delete(h, ContentLength)
+	if etag != "" {
+		h.Set(ETag, etag)
+	}
+	if !lastModified.IsZero() {
+		h.Set(LastModified, lastModified.UTC().Format(http.TimeFormat))
+	}
+	w.WriteHeader(http.StatusNotModified)
+}
+
+// JSONConditional marshals provided interface, sets the ETag header computed from the marshalled bytes and, for GET
+// and HEAD requests, returns 304 Not Modified when If-None-Match matches, otherwise returns JSON + status code.
+//
+// Preconditions are only evaluated for 2xx status codes. As the ETag is of the representation being written,
+// state-changing requests eg. PUT with If-Match, must call `CheckPreconditions` with the current ETag before
+// modifying the resource instead.
+func JSONConditional(w http.ResponseWriter, r *http.Request, status int, i interface{}, opt ETagOption) error {
+	b, err := json.Marshal(i)
+	if err != nil {
+		return err
+	}
+	return writeConditional(w, r, status, ApplicationJSON, nil, b, opt)
+}
+
+// XMLConditional marshals provided interface, sets the ETag header computed from the marshalled bytes and, for GET
+// and HEAD requests, returns 304 Not Modified when If-None-Match matches, otherwise returns XML + status code.
+//
+// Preconditions are only evaluated for 2xx status codes. As the ETag is of the representation being written,
+// state-changing requests eg. PUT with If-Match, must call `CheckPreconditions` with the current ETag before
+// modifying the resource instead.
+func XMLConditional(w http.ResponseWriter, r *http.Request, status int, i interface{}, opt ETagOption) error {
+	b, err := xml.Marshal(i)
+	if err != nil {
+		return err
+	}
+	return writeConditional(w, r, status, ApplicationXML, xmlHeaderBytes, b, opt)
+}
+
+func writeConditional(w http.ResponseWriter, r *http.Request, status int, contentType string, prefix, b []byte, opt ETagOption) (err error) {
+	if status >= 200 && status < 300 {
+		etag := GenerateETag(b, opt)
+		if r.Method == http.MethodGet || r.Method == http.MethodHead {
+			if inm := r.Header.Get(IfNoneMatch); inm != "" && etagListMatch(inm, etag, false) {
+				writeNotModified(w, etag, time.Time{})
+				return nil
+			}
+		}
+		w.Header().Set(ETag, etag)
+	}
+	w.Header().Set(ContentType, contentType)
+	w.WriteHeader(status)
+	if len(prefix) > 0 {
+		if _, err = w.Write(prefix); err != nil {
+			return
+		}
+	}
+	_, err = w.Write(b)
+	return
+}
@@ -0,0 +1,201 @@
+package httpext
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/go-playground/assert/v2"
+)
+
+func TestParseETags(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected []string
+	}{
+		{
+			name:     "single",
+			value:    `"abc"`,
+			expected: []string{`"abc"`},
+		},
+		{
+			name:     "list",
+			value:    `"abc", W/"def" ,"g,h"`,
+			expected: []string{`"abc"`, `W/"def"`, `"g,h"`},
+		},
+		{
+			name:     "any",
+			value:    `*`,
+			expected: []string{"*"},
+		},
+		{
+			name:     "invalid-skipped",
+			value:    `abc, "def"`,
+			expected: []string{`"def"`},
+		},
+		{
+			name:     "unterminated",
+			value:    `"abc`,
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			Equal(t, ParseETags(tt.value), tt.expected)
+		})
+	}
+}
+
+func TestETagMatch(t *testing.T) {
+	Equal(t, ETagStrongMatch(`"1"`, `"1"`), true)
+	Equal(t, ETagStrongMatch(`W/"1"`, `"1"`), false)
+	Equal(t, ETagStrongMatch(`W/"1"`, `W/"1"`), false)
+	Equal(t, ETagWeakMatch(`W/"1"`, `"1"`), true)
+	Equal(t, ETagWeakMatch(`W/"1"`, `W/"2"`), false)
+	Equal(t, GenerateETag([]byte("test"), StrongETag), GenerateETag([]byte("test"), StrongETag))
+	Equal(t, GenerateETag([]byte("test"), WeakETag)[:3], `W/"`)
+}
+
+func TestEvaluatePreconditions(t *testing.T) {
+	etag := `"v1"`
+	lastModified := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		method   string
+		headers  map[string]string
+		expected int
+	}{
+		{
+			name:     "no-conditions",
+			method:   http.MethodGet,
+			expected: 0,
+		},
+		{
+			name:     "if-none-match-weak-match",
+			method:   http.MethodGet,
+			headers:  map[string]string{IfNoneMatch: `"v0", W/"v1"`},
+			expected: http.StatusNotModified,
+		},
+		{
+			name:     "if-none-match-put",
+			method:   http.MethodPut,
+			headers:  map[string]string{IfNoneMatch: `*`},
+			expected: http.StatusPreconditionFailed,
+		},
+		{
+			name:     "if-none-match-no-match",
+			method:   http.MethodGet,
+			headers:  map[string]string{IfNoneMatch: `"v2"`, IfModifiedSince: lastModified.Format(http.TimeFormat)},
+			expected: 0,
+		},
+		{
+			name:     "if-match-strong",
+			method:   http.MethodPut,
+			headers:  map[string]string{IfMatch: `"v1"`},
+			expected: 0,
+		},
+		{
+			name:     "if-match-weak-fails",
+			method:   http.MethodPut,
+			headers:  map[string]string{IfMatch: `W/"v1"`},
+			expected: http.StatusPreconditionFailed,
+		},
+		{
+			name:     "if-unmodified-since-fails",
+			method:   http.MethodPut,
+			headers:  map[string]string{IfUnmodifiedSince: lastModified.Add(-time.Hour).Format(http.TimeFormat)},
+			expected: http.StatusPreconditionFailed,
+		},
+		{
+			name:     "if-unmodified-since-ok",
+			method:   http.MethodPut,
+			headers:  map[string]string{IfUnmodifiedSince: lastModified.Format(http.TimeFormat)},
+			expected: 0,
+		},
+		{
+			name:     "if-modified-since-not-modified",
+			method:   http.MethodGet,
+			headers:  map[string]string{IfModifiedSince: lastModified.Format(http.TimeFormat)},
+			expected: http.StatusNotModified,
+		},
+		{
+			name:     "if-modified-since-modified",
+			method:   http.MethodGet,
+			headers:  map[string]string{IfModifiedSince: lastModified.Add(-time.Hour).Format(http.TimeFormat)},
+			expected: 0,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, "/", nil)
+			for k, v := range tt.headers {
+				req.Header.Set(k, v)
+			}
+			Equal(t, EvaluatePreconditions(req, etag, lastModified), tt.expected)
+		})
+	}
+}
+
+func TestJSONConditional(t *testing.T) {
+	type test struct {
+		Field string `json:"field"`
+	}
+	tst := test{Field: "myfield"}
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	w := httptest.NewRecorder()
+	err := JSONConditional(w, req, http.StatusOK, tst, StrongETag)
+	Equal(t, err, nil)
+	Equal(t, w.Code, http.StatusOK)
+	Equal(t, w.Header().Get(ContentType), ApplicationJSON)
+	etag := w.Header().Get(ETag)
+	NotEqual(t, etag, "")
+
+	req.Header.Set(IfNoneMatch, etag)
+	w = httptest.NewRecorder()
+	err = JSONConditional(w, req, http.StatusOK, tst, StrongETag)
+	Equal(t, err, nil)
+	Equal(t, w.Code, http.StatusNotModified)
+	Equal(t, w.Header().Get(ETag), etag)
+	Equal(t, w.Body.Len(), 0)
+
+	req = httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(IfNoneMatch, etag)
+	w = httptest.NewRecorder()
+	err = XMLConditional(w, req, http.StatusOK, tst, WeakETag)
+	Equal(t, err, nil)
+	Equal(t, w.Code, http.StatusOK)
+	Equal(t, w.Header().Get(ContentType), ApplicationXML)
+
+	// the written representation is post-mutation so If-Match is not evaluated against it
+	req = httptest.NewRequest(http.MethodPut, "/", nil)
+	req.Header.Set(IfMatch, `"v0"`)
+	w = httptest.NewRecorder()
+	err = JSONConditional(w, req, http.StatusOK, tst, StrongETag)
+	Equal(t, err, nil)
+	Equal(t, w.Code, http.StatusOK)
+	Equal(t, w.Header().Get(ETag), etag)
+
+	req.Header.Set(IfNoneMatch, etag)
+	w = httptest.NewRecorder()
+	err = JSONConditional(w, req, http.StatusOK, tst, StrongETag)
+	Equal(t, err, nil)
+	Equal(t, w.Code, http.StatusOK)
+}
+
+func TestCheckPreconditions(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPut, "/", nil)
+	req.Header.Set(IfMatch, `"v0"`)
+	w := httptest.NewRecorder()
+	Equal(t, CheckPreconditions(w, req, `"v1"`, time.Time{}), true)
+	Equal(t, w.Code, http.StatusPreconditionFailed)
+
+	req.Header.Set(IfMatch, `"v1"`)
+	w = httptest.NewRecorder()
+	Equal(t, CheckPreconditions(w, req, `"v1"`, time.Time{}), false)
+}
@@ -0,0 +1,95 @@
+package httpext
+
+import (
+	"mime"
+	"strings"
+	"unicode/utf8"
+)
+
+// Content-Disposition types
+const (
+	DispositionAttachment string = "attachment"
+	DispositionInline     string = "inline"
+)
+
+const upperHex = "0123456789ABCDEF"
+
+// FormatContentDisposition returns an RFC 6266 compliant Content-Disposition header value for the provided disposition
+// type and filename.
+//
+// The filename is always emitted as a quoted-string with any non-ASCII or control characters replaced so that it
+// cannot break or inject into the header. When replacements were necessary the original filename is also
+// emitted, percent-encoded, as an RFC 5987 extended `filename*` parameter which takes precedence in clients
+// supporting it.
+func FormatContentDisposition(dispositionType, filename string) string {
+	var sb strings.Builder
+	sb.WriteString(dispositionType)
+	if filename == "" {
+		return sb.String()
+	}
+
+	var needsExtended bool
+	sb.WriteString(`; filename="`)
+	for _, r := range filename {
+		switch {
+		case r == '"' || r == '\\':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r < 0x20 || r == 0x7f || r >= utf8.RuneSelf:
+			needsExtended = true
+			sb.WriteByte('_')
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	sb.WriteByte('"')
+
+	if needsExtended {
+		sb.WriteString("; filename*=UTF-8''")
+		for i := 0; i < len(filename); i++ {
+			c := filename[i]
+			if isAttrChar(c) {
+				sb.WriteByte(c)
+				continue
+			}
+			sb.WriteByte('%')
+			sb.WriteByte(upperHex[c>>4])
+			sb.WriteByte(upperHex[c&0x0f])
+		}
+	}
+	return sb.String()
+}
+
+// isAttrChar reports if the byte is an RFC 5987 attr-char which does not require percent-encoding.
+func isAttrChar(c byte) bool {
+	switch {
+	case c >= 'a' && c <= 'z', c >= 'A' && c <= 'Z', c >= '0' && c <= '9':
+		return true
+	}
+	switch c {
+	case '!', '#', '$', '&', '+', '-', '.', '^', '_', '`', '|', '~':
+		return true
+	}
+	return false
+}
+
+// ParseContentDisposition parses a Content-Disposition header value returning the lower-cased disposition type and
+// the filename, if any.
+//
+// The RFC 5987 `filename*` parameter takes precedence over `filename` when both are present. Any directory components
+// of the filename are stripped so that it is safe to use as a local file name.
+func ParseContentDisposition(value string) (dispositionType, filename string, err error) {
+	dispositionType, params, err := mime.ParseMediaType(value)
+	if err != nil {
+		return "", "", err
+	}
+	filename = params["filename"]
+	if idx := strings.LastIndexAny(filename, `/\`); idx != -1 {
+		filename = filename[idx+1:]
+	}
+	switch filename {
+	case ".", "..":
+		filename = ""
+	}
+	return dispositionType, filename, nil
+}
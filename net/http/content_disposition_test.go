@@ -0,0 +1,94 @@
+//go:build go1.18
+// +build go1.18
+
+package httpext
+
+import (
+	"net/http"
+	"testing"
+
+	. "github.com/go-playground/assert/v2"
+)
+
+func TestFormatContentDisposition(t *testing.T) {
+	tests := []struct {
+		name     string
+		typ      string
+		filename string
+		expected string
+	}{
+		{
+			name:     "plain",
+			typ:      DispositionAttachment,
+			filename: "README.md",
+			expected: `attachment; filename="README.md"`,
+		},
+		{
+			name:     "spaces-and-semicolons",
+			typ:      DispositionInline,
+			filename: "my file;v2.txt",
+			expected: `inline; filename="my file;v2.txt"`,
+		},
+		{
+			name:     "quotes",
+			typ:      DispositionAttachment,
+			filename: `a"b\c.txt`,
+			expected: `attachment; filename="a\"b\\c.txt"`,
+		},
+		{
+			name:     "injection",
+			typ:      DispositionAttachment,
+			filename: "a\r\nSet-Cookie: x=y",
+			expected: `attachment; filename="a__Set-Cookie: x=y"; filename*=UTF-8''a%0D%0ASet-Cookie%3A%20x%3Dy`,
+		},
+		{
+			name:     "non-ascii",
+			typ:      DispositionAttachment,
+			filename: "€ rates.pdf",
+			expected: `attachment; filename="_ rates.pdf"; filename*=UTF-8''%E2%82%AC%20rates.pdf`,
+		},
+		{
+			name:     "empty",
+			typ:      DispositionInline,
+			filename: "",
+			expected: `inline`,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			Equal(t, FormatContentDisposition(tt.typ, tt.filename), tt.expected)
+		})
+	}
+}
+
+func TestParseContentDisposition(t *testing.T) {
+	for _, filename := range []string{"README.md", "my file;v2.txt", `a"b.txt`, "€ rates.pdf"} {
+		typ, parsed, err := ParseContentDisposition(FormatContentDisposition(DispositionAttachment, filename))
+		Equal(t, err, nil)
+		Equal(t, typ, DispositionAttachment)
+		Equal(t, parsed, filename)
+	}
+
+	typ, filename, err := ParseContentDisposition(`Attachment; filename="../../etc/passwd"`)
+	Equal(t, err, nil)
+	Equal(t, typ, DispositionAttachment)
+	Equal(t, filename, "passwd")
+
+	_, filename, err = ParseContentDisposition(`inline`)
+	Equal(t, err, nil)
+	Equal(t, filename, "")
+
+	_, _, err = ParseContentDisposition(`attachment; filename="unterminated`)
+	NotEqual(t, err, nil)
+}
+
+func TestHasFilename(t *testing.T) {
+	headers := make(http.Header)
+	Equal(t, HasFilename(headers).IsNone(), true)
+
+	headers.Set(ContentDisposition, FormatContentDisposition(DispositionAttachment, "résumé.pdf"))
+	filename := HasFilename(headers)
+	Equal(t, filename.IsSome(), true)
+	Equal(t, filename.Unwrap(), "résumé.pdf")
+}
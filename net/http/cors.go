@@ -0,0 +1,226 @@
+package httpext
+
+import (
+	"net/http"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// CORS is a configurable Cross-Origin Resource Sharing middleware.
+type CORS struct {
+	origins         []string
+	wildcards       [][2]string
+	originFn        func(r *http.Request, origin string) bool
+	methods         []string
+	headers         []string
+	exposed         []string
+	maxAge          time.Duration
+	allowAll        bool
+	allowAllHeaders bool
+	credentials     bool
+}
+
+// NewCORS returns a new `CORS` middleware with sane default values.
+//
+// The default values are:
+//   - No origins are allowed.
+//   - `AllowedMethods` are GET, HEAD and POST.
+//   - No `AllowedHeaders` and so preflights requesting any headers are rejected.
+//   - `AllowCredentials` is false.
+//   - `MaxAge` is 0 and so Access-Control-Max-Age is not sent.
+func NewCORS() CORS {
+	return CORS{
+		methods: []string{http.MethodGet, http.MethodHead, http.MethodPost},
+	}
+}
+
+// AllowedOrigins sets the allowed origins, replacing any previously set, which are matched case-insensitively.
+//
+// An origin may be "*" to allow any origin, or contain a single wildcard subdomain eg. "https://*.example.com"
+// which matches "https://api.example.com" but not "https://example.com".
+func (c CORS) AllowedOrigins(origins ...string) CORS {
+	c.origins, c.wildcards, c.allowAll = nil, nil, false
+	for _, o := range origins {
+		o = strings.ToLower(o)
+		switch {
+		case o == "*":
+			c.allowAll = true
+		case strings.Contains(o, "*"):
+			idx := strings.IndexByte(o, '*')
+			c.wildcards = append(c.wildcards, [2]string{o[:idx], o[idx+1:]})
+		default:
+			c.origins = append(c.origins, o)
+		}
+	}
+	return c
+}
+
+// AllowOriginFn sets a predicate used to allow origins in addition to the `AllowedOrigins`.
+func (c CORS) AllowOriginFn(fn func(r *http.Request, origin string) bool) CORS {
+	c.originFn = fn
+	return c
+}
+
+// AllowedMethods sets the methods allowed for cross-origin requests, replacing any previously set.
+func (c CORS) AllowedMethods(methods ...string) CORS {
+	c.methods = make([]string, len(methods))
+	for i, m := range methods {
+		c.methods[i] = strings.ToUpper(m)
+	}
+	return c
+}
+
+// AllowedHeaders sets the request headers allowed for cross-origin requests, replacing any previously set. A header
+// of "*" allows any header.
+//
+// Browsers only request safelisted headers, such as Content-Type, when their value is not safelisted eg.
+// "application/json", so they must also be allowed explicitly.
+func (c CORS) AllowedHeaders(headers ...string) CORS {
+	c.headers, c.allowAllHeaders = nil, false
+	for _, h := range headers {
+		if h == "*" {
+			c.allowAllHeaders = true
+			continue
+		}
+		c.headers = append(c.headers, textproto.CanonicalMIMEHeaderKey(h))
+	}
+	return c
+}
+
+// ExposedHeaders sets the response headers exposed to cross-origin requests, replacing any previously set.
+func (c CORS) ExposedHeaders(headers ...string) CORS {
+	c.exposed = make([]string, len(headers))
+	copy(c.exposed, headers)
+	return c
+}
+
+// AllowCredentials sets whether credentials, such as cookies, are allowed for cross-origin requests.
+//
+// When true the requesting origin is always echoed back rather than "*", as required by the Fetch specification.
+func (c CORS) AllowCredentials(allow bool) CORS {
+	c.credentials = allow
+	return c
+}
+
+// MaxAge sets how long the results of a preflight request may be cached by the client.
+func (c CORS) MaxAge(maxAge time.Duration) CORS {
+	c.maxAge = maxAge
+	return c
+}
+
+// Handler returns an http.Handler which applies the CORS policy before calling next.
+//
+// Preflight requests are answered directly, with 204 if allowed or 403 without any CORS headers if the origin,
+// method or any requested header is not allowed, and never reach next.
+func (c CORS) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get(Origin)
+		h := w.Header()
+
+		if r.Method == http.MethodOptions && origin != "" && r.Header.Get(AccessControlRequestMethod) != "" {
+			h.Add(Vary, Origin)
+			h.Add(Vary, AccessControlRequestMethod)
+			h.Add(Vary, AccessControlRequestHeaders)
+			c.preflight(w, r, origin)
+			return
+		}
+
+		h.Add(Vary, Origin)
+		if origin != "" && c.isOriginAllowed(r, origin) {
+			c.setAllowOrigin(h, origin)
+			if len(c.exposed) > 0 {
+				h.Set(AccessControlExposeHeaders, strings.Join(c.exposed, ", "))
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (c CORS) preflight(w http.ResponseWriter, r *http.Request, origin string) {
+	method := strings.ToUpper(r.Header.Get(AccessControlRequestMethod))
+	requested := parseCORSHeaders(r.Header.Values(AccessControlRequestHeaders))
+
+	if !c.isOriginAllowed(r, origin) || !c.isMethodAllowed(method) || !c.areHeadersAllowed(requested) {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+
+	h := w.Header()
+	c.setAllowOrigin(h, origin)
+	h.Set(AccessControlAllowMethods, method)
+	if len(requested) > 0 {
+		h.Set(AccessControlAllowHeaders, strings.Join(requested, ", "))
+	}
+	if c.maxAge > 0 {
+		h.Set(AccessControlMaxAge, strconv.FormatInt(int64(c.maxAge/time.Second), 10))
+	}
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (c CORS) setAllowOrigin(h http.Header, origin string) {
+	if c.allowAll && !c.credentials {
+		h.Set(AccessControlAllowOrigin, "*")
+	} else {
+		h.Set(AccessControlAllowOrigin, origin)
+	}
+	if c.credentials {
+		h.Set(AccessControlAllowCredentials, "true")
+	}
+}
+
+func (c CORS) isOriginAllowed(r *http.Request, origin string) bool {
+	if c.allowAll {
+		return true
+	}
+	lower := strings.ToLower(origin)
+	for _, o := range c.origins {
+		if o == lower {
+			return true
+		}
+	}
+	for _, w := range c.wildcards {
+		if len(lower) > len(w[0])+len(w[1]) && strings.HasPrefix(lower, w[0]) && strings.HasSuffix(lower, w[1]) {
+			return true
+		}
+	}
+	return c.originFn != nil && c.originFn(r, origin)
+}
+
+func (c CORS) isMethodAllowed(method string) bool {
+	for _, m := range c.methods {
+		if m == method {
+			return true
+		}
+	}
+	return false
+}
+
+func (c CORS) areHeadersAllowed(requested []string) bool {
+	if c.allowAllHeaders {
+		return true
+	}
+REQUESTED:
+	for _, r := range requested {
+		for _, h := range c.headers {
+			if h == r {
+				continue REQUESTED
+			}
+		}
+		return false
+	}
+	return true
+}
+
+// parseCORSHeaders parses the comma separated Access-Control-Request-Headers values into canonical header keys.
+func parseCORSHeaders(values []string) (headers []string) {
+	for _, v := range values {
+		for _, h := range strings.Split(v, ",") {
+			if h = strings.TrimSpace(h); h != "" {
+				headers = append(headers, textproto.CanonicalMIMEHeaderKey(h))
+			}
+		}
+	}
+	return
+}
@@ -0,0 +1,192 @@
+package httpext
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/go-playground/assert/v2"
+)
+
+func TestCORS(t *testing.T) {
+	cors := NewCORS().
+		AllowedOrigins("https://example.com", "https://*.example.org").
+		AllowOriginFn(func(_ *http.Request, origin string) bool { return origin == "https://predicate.com" }).
+		AllowedMethods(http.MethodGet, http.MethodPut).
+		AllowedHeaders("x-custom", "content-type").
+		ExposedHeaders("X-Total").
+		MaxAge(time.Hour)
+
+	var called bool
+	handler := cors.Handler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		called = true
+		w.WriteHeader(http.StatusOK)
+	}))
+
+	tests := []struct {
+		name            string
+		method          string
+		headers         map[string]string
+		expectedStatus  int
+		expectedOrigin  string
+		expectedHeaders map[string]string
+		expectedCalled  bool
+	}{
+		{
+			name:           "no origin",
+			method:         http.MethodGet,
+			expectedStatus: http.StatusOK,
+			expectedCalled: true,
+		},
+		{
+			name:            "exact origin",
+			method:          http.MethodGet,
+			headers:         map[string]string{Origin: "https://EXAMPLE.com"},
+			expectedStatus:  http.StatusOK,
+			expectedOrigin:  "https://EXAMPLE.com",
+			expectedHeaders: map[string]string{AccessControlExposeHeaders: "X-Total"},
+			expectedCalled:  true,
+		},
+		{
+			name:           "wildcard subdomain origin",
+			method:         http.MethodGet,
+			headers:        map[string]string{Origin: "https://api.example.org"},
+			expectedStatus: http.StatusOK,
+			expectedOrigin: "https://api.example.org",
+			expectedCalled: true,
+		},
+		{
+			name:           "wildcard does not match apex",
+			method:         http.MethodGet,
+			headers:        map[string]string{Origin: "https://.example.org"},
+			expectedStatus: http.StatusOK,
+			expectedCalled: true,
+		},
+		{
+			name:           "predicate origin",
+			method:         http.MethodGet,
+			headers:        map[string]string{Origin: "https://predicate.com"},
+			expectedStatus: http.StatusOK,
+			expectedOrigin: "https://predicate.com",
+			expectedCalled: true,
+		},
+		{
+			name:           "disallowed origin",
+			method:         http.MethodGet,
+			headers:        map[string]string{Origin: "https://evil.com"},
+			expectedStatus: http.StatusOK,
+			expectedCalled: true,
+		},
+		{
+			name:   "preflight",
+			method: http.MethodOptions,
+			headers: map[string]string{
+				Origin:                      "https://example.com",
+				AccessControlRequestMethod:  http.MethodPut,
+				AccessControlRequestHeaders: "X-Custom, content-type",
+			},
+			expectedStatus: http.StatusNoContent,
+			expectedOrigin: "https://example.com",
+			expectedHeaders: map[string]string{
+				AccessControlAllowMethods: http.MethodPut,
+				AccessControlAllowHeaders: "X-Custom, Content-Type",
+				AccessControlMaxAge:       "3600",
+			},
+		},
+		{
+			name:           "preflight disallowed origin",
+			method:         http.MethodOptions,
+			headers:        map[string]string{Origin: "https://evil.com", AccessControlRequestMethod: http.MethodGet},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "preflight disallowed method",
+			method:         http.MethodOptions,
+			headers:        map[string]string{Origin: "https://example.com", AccessControlRequestMethod: http.MethodDelete},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:   "preflight disallowed header",
+			method: http.MethodOptions,
+			headers: map[string]string{
+				Origin:                      "https://example.com",
+				AccessControlRequestMethod:  http.MethodGet,
+				AccessControlRequestHeaders: "X-Other",
+			},
+			expectedStatus: http.StatusForbidden,
+		},
+		{
+			name:           "non preflight options",
+			method:         http.MethodOptions,
+			headers:        map[string]string{Origin: "https://example.com"},
+			expectedStatus: http.StatusOK,
+			expectedOrigin: "https://example.com",
+			expectedCalled: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			called = false
+			req := httptest.NewRequest(tc.method, "/", nil)
+			for k, v := range tc.headers {
+				req.Header.Set(k, v)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			Equal(t, w.Code, tc.expectedStatus)
+			Equal(t, called, tc.expectedCalled)
+			Equal(t, w.Header().Get(AccessControlAllowOrigin), tc.expectedOrigin)
+			Equal(t, w.Header().Values(Vary)[0], Origin)
+			for k, v := range tc.expectedHeaders {
+				Equal(t, w.Header().Get(k), v)
+			}
+			if tc.expectedOrigin == "" {
+				for k := range w.Header() {
+					Equal(t, strings.HasPrefix(k, "Access-Control-"), false)
+				}
+			}
+		})
+	}
+}
+
+func TestCORSDefaultHeaders(t *testing.T) {
+	handler := NewCORS().AllowedOrigins("https://example.com").Handler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+
+	// a JSON POST preflight requests Content-Type which must be explicitly allowed
+	req := httptest.NewRequest(http.MethodOptions, "/", nil)
+	req.Header.Set(Origin, "https://example.com")
+	req.Header.Set(AccessControlRequestMethod, http.MethodPost)
+	req.Header.Set(AccessControlRequestHeaders, "content-type")
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	Equal(t, w.Code, http.StatusForbidden)
+
+	req.Header.Del(AccessControlRequestHeaders)
+	w = httptest.NewRecorder()
+	handler.ServeHTTP(w, req)
+	Equal(t, w.Code, http.StatusNoContent)
+}
+
+func TestCORSAllowAll(t *testing.T) {
+	handler := func(cors CORS) http.Handler {
+		return cors.Handler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {}))
+	}
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.Header.Set(Origin, "https://example.com")
+
+	cors := NewCORS().AllowedOrigins("*")
+	w := httptest.NewRecorder()
+	handler(cors).ServeHTTP(w, req)
+	Equal(t, w.Header().Get(AccessControlAllowOrigin), "*")
+	Equal(t, w.Header().Get(AccessControlAllowCredentials), "")
+
+	w = httptest.NewRecorder()
+	handler(cors.AllowCredentials(true)).ServeHTTP(w, req)
+	Equal(t, w.Header().Get(AccessControlAllowOrigin), "https://example.com")
+	Equal(t, w.Header().Get(AccessControlAllowCredentials), "true")
+}
@@ -0,0 +1,190 @@
+//go:build go1.18
+// +build go1.18
+
+package httpext
+
+import (
+	"compress/gzip"
+	"encoding/csv"
+	"errors"
+	"io"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	bytesext "github.com/go-playground/pkg/v5/bytes"
+	ioext "github.com/go-playground/pkg/v5/io"
+	. "github.com/go-playground/pkg/v5/values/option"
+)
+
+// ErrCSVRow is returned when a CSV row fails to be parsed or decoded.
+type ErrCSVRow struct {
+	// Line is the line number of the row, starting at 1 for the header row.
+	Line int
+
+	// Err is the parse or decode error of the row.
+	Err error
+}
+
+// Error returns the error message for the CSV row.
+func (e ErrCSVRow) Error() string {
+	return "csv line " + strconv.Itoa(e.Line) + ": " + e.Err.Error()
+}
+
+// Unwrap returns the parse or decode error of the row.
+func (e ErrCSVRow) Unwrap() error {
+	return e.Err
+}
+
+// DecodeCSV decodes the CSV request body into a slice of `T` and limits the request size via an ioext.LimitReader
+// using the maxMemory param.
+//
+// The first row must be a header row whose columns are mapped to the struct fields of `T` using their `form` tag,
+// and each cell converted using the `DefaultFormDecoder`, exactly as query params and forms are. Empty cells are
+// treated as absent and unknown columns are ignored. Decoding stops at the first invalid row returning an
+// `ErrCSVRow`.
+//
+// The body is transcoded to UTF-8 from the Content-Type charset and a leading UTF-8 byte order mark is ignored.
+func DecodeCSV[T any](r *http.Request, maxMemory bytesext.Bytes) (results []T, err error) {
+	var body io.Reader = r.Body
+	if encoding := r.Header.Get(ContentEncoding); encoding == Gzip {
+		var gzr *gzip.Reader
+		gzr, err = gzip.NewReader(body)
+		if err != nil {
+			return
+		}
+		defer func() {
+			_ = gzr.Close()
+		}()
+		body = gzr
+	}
+	cr := csv.NewReader(contentTypeReader(r.Header.Get(ContentType), ioext.LimitReader(body, maxMemory)))
+	header, err := cr.Read()
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, nil
+		}
+		return nil, ErrCSVRow{Line: 1, Err: err}
+	}
+	if len(header) > 0 {
+		header[0] = strings.TrimPrefix(header[0], "\ufeff")
+	}
+
+	for {
+		record, err := cr.Read()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return results, nil
+			}
+			var pe *csv.ParseError
+			if errors.As(err, &pe) {
+				return nil, ErrCSVRow{Line: pe.StartLine, Err: pe.Err}
+			}
+			return nil, err
+		}
+		line, _ := cr.FieldPos(0)
+
+		values := make(url.Values, len(header))
+		for i, column := range header {
+			if record[i] != "" {
+				values[column] = []string{record[i]}
+			}
+		}
+		var v T
+		if err = DefaultFormDecoder.Decode(&v, values); err != nil {
+			return nil, ErrCSVRow{Line: line, Err: err}
+		}
+		results = append(results, v)
+	}
+}
+
+// CSV streams the provided items as a CSV attachment with the provided filename, which may be empty to omit the
+// Content-Disposition.
+//
+// The header row is derived from the `form` tags of the struct fields of `T`, in declaration order with the fields of
+// embedded structs flattened, and each value converted using the `DefaultFormEncoder`.
+func CSV[T any](w http.ResponseWriter, status int, filename string, items []T) error {
+	var i int
+	return CSVFromIterator(w, status, filename, func() Option[T] {
+		if i >= len(items) {
+			return None[T]()
+		}
+		i++
+		return Some(items[i-1])
+	})
+}
+
+// CSVFromIterator streams the values returned by the provided iterator function as a CSV attachment, with the
+// provided filename, until it returns None. See `CSV` for details.
+func CSVFromIterator[T any](w http.ResponseWriter, status int, filename string, next func() Option[T]) error {
+	columns, err := csvColumns(reflect.TypeOf((*T)(nil)).Elem())
+	if err != nil {
+		return err
+	}
+
+	w.Header().Set(ContentType, TextCSV+charsetUTF8)
+	if filename != "" {
+		w.Header().Set(ContentDisposition, FormatContentDisposition(DispositionAttachment, filename))
+	}
+	w.WriteHeader(status)
+
+	cw := csv.NewWriter(w)
+	if err = cw.Write(columns); err != nil {
+		return err
+	}
+	row := make([]string, len(columns))
+	for {
+		item := next()
+		if item.IsNone() {
+			break
+		}
+		values, err := DefaultFormEncoder.Encode(item.Unwrap())
+		if err != nil {
+			return err
+		}
+		for i, column := range columns {
+			row[i] = values.Get(column)
+		}
+		if err = cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// csvColumns returns the column names of the struct fields of the type.
+func csvColumns(typ reflect.Type) (columns []string, err error) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil, errors.New("csv: type must be a struct or pointer to a struct, got " + typ.String())
+	}
+	return appendCSVColumns(nil, typ), nil
+}
+
+// appendCSVColumns appends the column names of the struct fields of the type, flattening the fields of embedded
+// structs as the `DefaultFormEncoder` does.
+func appendCSVColumns(columns []string, typ reflect.Type) []string {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.Anonymous {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Ptr {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct && embedded != reflect.TypeOf(time.Time{}) && field.Tag.Get(formTagName) != "-" {
+				columns = appendCSVColumns(columns, embedded)
+			}
+			continue
+		}
+		if name, ok := formFieldName(field); ok {
+			columns = append(columns, name)
+		}
+	}
+	return columns
+}
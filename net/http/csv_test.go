@@ -0,0 +1,138 @@
+//go:build go1.18
+// +build go1.18
+
+package httpext
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/go-playground/assert/v2"
+	ioext "github.com/go-playground/pkg/v5/io"
+	. "github.com/go-playground/pkg/v5/values/option"
+)
+
+type csvUser struct {
+	ID      int     `form:"id"`
+	Name    string  `form:"name"`
+	Active  bool    `form:"active"`
+	Balance float64 `form:"balance,omitempty"`
+	Ignored string  `form:"-"`
+}
+
+func TestDecodeCSV(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		maxMemory   int64
+		expected    []csvUser
+		expectedErr string
+	}{
+		{
+			name:      "valid",
+			body:      "\ufeffname,id,active,unknown\n\"Doe, John\",1,true,x\nJane,2,,\n",
+			maxMemory: 1024,
+			expected:  []csvUser{{ID: 1, Name: "Doe, John", Active: true}, {ID: 2, Name: "Jane"}},
+		},
+		{
+			name:      "empty",
+			body:      "",
+			maxMemory: 1024,
+		},
+		{
+			name:        "invalid conversion",
+			body:        "id,name\n1,a\nabc,b\n",
+			maxMemory:   1024,
+			expectedErr: "csv line 3: Field Namespace:id ERROR:Invalid Integer Value 'abc' Type 'int' Namespace 'id'",
+		},
+		{
+			name:        "wrong number of fields",
+			body:        "id,name\n1,a,extra\n",
+			maxMemory:   1024,
+			expectedErr: "csv line 2: wrong number of fields",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(tc.body))
+			req.Header.Set(ContentType, TextCSV)
+			results, err := DecodeCSV[csvUser](req, tc.maxMemory)
+			if tc.expectedErr != "" {
+				NotEqual(t, err, nil)
+				Equal(t, err.Error(), tc.expectedErr)
+				return
+			}
+			Equal(t, err, nil)
+			Equal(t, results, tc.expected)
+		})
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader("id,name\n1,"+strings.Repeat("a", 100)+"\n"))
+	_, err := DecodeCSV[csvUser](req, 50)
+	Equal(t, errors.Is(err, ioext.ErrLimitedReaderEOF), true)
+}
+
+func TestCSV(t *testing.T) {
+	w := httptest.NewRecorder()
+	err := CSV(w, http.StatusOK, "users.csv", []csvUser{{ID: 1, Name: "Doe, John", Active: true, Balance: 1.5}, {ID: 2, Name: "Jane"}})
+	Equal(t, err, nil)
+	Equal(t, w.Code, http.StatusOK)
+	Equal(t, w.Header().Get(ContentType), "text/csv; charset=utf-8")
+	Equal(t, w.Header().Get(ContentDisposition), `attachment; filename="users.csv"`)
+	Equal(t, w.Body.String(), "id,name,active,balance\n1,\"Doe, John\",true,1.5\n2,Jane,false,\n")
+
+	// round trip
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(w.Body.String()))
+	results, err := DecodeCSV[*csvUser](req, 1024)
+	Equal(t, err, nil)
+	Equal(t, len(results), 2)
+	Equal(t, *results[0], csvUser{ID: 1, Name: "Doe, John", Active: true, Balance: 1.5})
+
+	var count int
+	w = httptest.NewRecorder()
+	err = CSVFromIterator(w, http.StatusOK, "", func() Option[*csvUser] {
+		if count == 2 {
+			return None[*csvUser]()
+		}
+		count++
+		return Some(&csvUser{ID: count})
+	})
+	Equal(t, err, nil)
+	Equal(t, w.Header().Get(ContentDisposition), "")
+	Equal(t, w.Body.String(), "id,name,active,balance\n1,,false,\n2,,false,\n")
+
+	Equal(t, CSV(httptest.NewRecorder(), http.StatusOK, "", []int{1}) != nil, true)
+}
+
+func TestCSVEmbedded(t *testing.T) {
+	type base struct {
+		ID int `form:"id"`
+	}
+	type Audit struct {
+		CreatedBy string `form:"created_by"`
+	}
+	type row struct {
+		base
+		*Audit
+		Name string `form:"name"`
+	}
+
+	w := httptest.NewRecorder()
+	err := CSV(w, http.StatusOK, "", []row{{base: base{ID: 1}, Audit: &Audit{CreatedBy: "admin"}, Name: "a"}})
+	Equal(t, err, nil)
+	Equal(t, w.Body.String(), "id,created_by,name\n1,admin,a\n")
+
+	req := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(w.Body.String()))
+	req.Header.Set(ContentType, TextCSV)
+	results, err := DecodeCSV[row](req, 1024)
+	Equal(t, err, nil)
+	Equal(t, len(results), 1)
+	Equal(t, results[0].ID, 1)
+	Equal(t, results[0].Name, "a")
+	Equal(t, results[0].Audit.CreatedBy, "admin")
+}
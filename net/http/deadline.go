@@ -0,0 +1,152 @@
+package httpext
+
+import (
+	"context"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// TimeoutFormat is the encoding of a timeout header value.
+type TimeoutFormat uint8
+
+const (
+	// TimeoutMilliseconds encodes the timeout as a non-negative integer number of milliseconds eg. "1500".
+	TimeoutMilliseconds TimeoutFormat = iota
+
+	// TimeoutGRPC encodes the timeout using the gRPC grpc-timeout format of at most 8 digits followed by a unit of
+	// H, M, S, m, u or n eg. "1500m".
+	TimeoutGRPC
+)
+
+// maxGRPCTimeoutValue is the maximum value, 8 digits, allowed by the grpc-timeout format.
+const maxGRPCTimeoutValue = 99999999
+
+var errInvalidTimeout = errors.New("invalid timeout")
+
+// FormatTimeout returns the timeout encoded in the provided format, with negative timeouts encoded as 0.
+func FormatTimeout(timeout time.Duration, format TimeoutFormat) string {
+	if timeout < 0 {
+		timeout = 0
+	}
+	switch format {
+	case TimeoutGRPC:
+		// use the most precise unit which fits within 8 digits
+		units := []struct {
+			unit byte
+			d    time.Duration
+		}{
+			{'n', time.Nanosecond}, {'u', time.Microsecond}, {'m', time.Millisecond},
+			{'S', time.Second}, {'M', time.Minute}, {'H', time.Hour},
+		}
+		for _, u := range units {
+			if v := timeout / u.d; v <= maxGRPCTimeoutValue {
+				return strconv.FormatInt(int64(v), 10) + string(u.unit)
+			}
+		}
+		return strconv.FormatInt(maxGRPCTimeoutValue, 10) + "H"
+	default:
+		return strconv.FormatInt(int64(timeout/time.Millisecond), 10)
+	}
+}
+
+// ParseTimeout parses a timeout encoded in the provided format.
+func ParseTimeout(s string, format TimeoutFormat) (time.Duration, error) {
+	switch format {
+	case TimeoutGRPC:
+		if len(s) < 2 || len(s) > 9 {
+			return 0, errInvalidTimeout
+		}
+		v, err := strconv.ParseUint(s[:len(s)-1], 10, 64)
+		if err != nil {
+			return 0, errInvalidTimeout
+		}
+		var unit time.Duration
+		switch s[len(s)-1] {
+		case 'H':
+			unit = time.Hour
+		case 'M':
+			unit = time.Minute
+		case 'S':
+			unit = time.Second
+		case 'm':
+			unit = time.Millisecond
+		case 'u':
+			unit = time.Microsecond
+		case 'n':
+			unit = time.Nanosecond
+		default:
+			return 0, errInvalidTimeout
+		}
+		// eg. 99999999H is valid but not representable as a time.Duration
+		if v > uint64(math.MaxInt64/int64(unit)) {
+			return 0, errInvalidTimeout
+		}
+		return time.Duration(v) * unit, nil
+	default:
+		v, err := strconv.ParseUint(s, 10, 32)
+		if err != nil {
+			return 0, errInvalidTimeout
+		}
+		return time.Duration(v) * time.Millisecond, nil
+	}
+}
+
+// Deadline is a middleware which reads the callers timeout from a request header and applies it as the request
+// context deadline, allowing work the caller has already abandoned to be stopped.
+//
+// The `Retryer` stamps the remaining context budget onto outgoing requests, see `Retryer.DeadlineHeader`.
+type Deadline struct {
+	header     string
+	format     TimeoutFormat
+	maxTimeout time.Duration
+}
+
+// NewDeadline returns a new `Deadline` with sane default values.
+//
+// The default values are:
+//   - `Header` is `X-Request-Timeout` using `TimeoutMilliseconds`.
+//   - `MaxTimeout` is 0 and so incoming timeouts are not capped.
+func NewDeadline() Deadline {
+	return Deadline{
+		header: XRequestTimeout,
+		format: TimeoutMilliseconds,
+	}
+}
+
+// Header sets the header, and its format, the timeout is read from eg. `GRPCTimeout` with `TimeoutGRPC`.
+func (d Deadline) Header(header string, format TimeoutFormat) Deadline {
+	d.header, d.format = header, format
+	return d
+}
+
+// MaxTimeout caps the timeout a caller can request. A value of 0 disables the cap.
+func (d Deadline) MaxTimeout(timeout time.Duration) Deadline {
+	d.maxTimeout = timeout
+	return d
+}
+
+// Handler returns an http.Handler which applies the timeout, if present and valid, as the request context deadline
+// before calling next. An invalid timeout is ignored.
+func (d Deadline) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		value := r.Header.Get(d.header)
+		if value == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		timeout, err := ParseTimeout(value, d.format)
+		if err != nil {
+			next.ServeHTTP(w, r)
+			return
+		}
+		if d.maxTimeout > 0 && timeout > d.maxTimeout {
+			timeout = d.maxTimeout
+		}
+		ctx, cancel := context.WithTimeout(r.Context(), timeout)
+		defer cancel()
+		next.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
@@ -0,0 +1,90 @@
+package httpext
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/go-playground/assert/v2"
+)
+
+func TestTimeoutFormat(t *testing.T) {
+	tests := []struct {
+		name     string
+		timeout  time.Duration
+		format   TimeoutFormat
+		expected string
+	}{
+		{name: "millis", timeout: 1500 * time.Millisecond, format: TimeoutMilliseconds, expected: "1500"},
+		{name: "millis negative", timeout: -time.Second, format: TimeoutMilliseconds, expected: "0"},
+		{name: "grpc nanos", timeout: 50 * time.Millisecond, format: TimeoutGRPC, expected: "50000000n"},
+		{name: "grpc micros", timeout: 1500 * time.Millisecond, format: TimeoutGRPC, expected: "1500000u"},
+		{name: "grpc millis", timeout: time.Hour, format: TimeoutGRPC, expected: "3600000m"},
+		{name: "grpc seconds", timeout: 48 * time.Hour, format: TimeoutGRPC, expected: "172800S"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			value := FormatTimeout(tc.timeout, tc.format)
+			Equal(t, value, tc.expected)
+			timeout, err := ParseTimeout(value, tc.format)
+			Equal(t, err, nil)
+			if tc.timeout < 0 {
+				tc.timeout = 0
+			}
+			Equal(t, timeout, tc.timeout)
+		})
+	}
+
+	for _, s := range []string{"", "1", "123456789S", "10x", "-1S", "99999999H"} {
+		_, err := ParseTimeout(s, TimeoutGRPC)
+		NotEqual(t, err, nil)
+	}
+	for _, s := range []string{"", "-1", "1.5", "abc"} {
+		_, err := ParseTimeout(s, TimeoutMilliseconds)
+		NotEqual(t, err, nil)
+	}
+}
+
+func TestDeadline(t *testing.T) {
+	var remaining time.Duration
+	var hasDeadline bool
+	next := http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+		var deadline time.Time
+		deadline, hasDeadline = r.Context().Deadline()
+		remaining = time.Until(deadline)
+	})
+
+	tests := []struct {
+		name        string
+		deadline    Deadline
+		header      string
+		value       string
+		hasDeadline bool
+		max         time.Duration
+	}{
+		{name: "none", deadline: NewDeadline()},
+		{name: "invalid", deadline: NewDeadline(), header: XRequestTimeout, value: "abc"},
+		{name: "millis", deadline: NewDeadline(), header: XRequestTimeout, value: "2000", hasDeadline: true, max: 2 * time.Second},
+		{name: "capped", deadline: NewDeadline().MaxTimeout(time.Second), header: XRequestTimeout, value: "5000", hasDeadline: true, max: time.Second},
+		{name: "grpc", deadline: NewDeadline().Header(GRPCTimeout, TimeoutGRPC), header: GRPCTimeout, value: "3S", hasDeadline: true, max: 3 * time.Second},
+		{name: "grpc overflow", deadline: NewDeadline().Header(GRPCTimeout, TimeoutGRPC), header: GRPCTimeout, value: "99999999H"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.header != "" {
+				req.Header.Set(tc.header, tc.value)
+			}
+			tc.deadline.Handler(next).ServeHTTP(httptest.NewRecorder(), req)
+			Equal(t, hasDeadline, tc.hasDeadline)
+			if tc.hasDeadline {
+				Equal(t, remaining <= tc.max && remaining > tc.max-time.Second, true)
+			}
+		})
+	}
+}
@@ -2,10 +2,15 @@ package httpext
 
 import (
 	"net/url"
+	"reflect"
+	"strings"
 
 	"github.com/go-playground/form/v4"
 )
 
+// formTagName is the struct tag used by the `DefaultFormDecoder` and `DefaultFormEncoder` to name fields.
+const formTagName = "form"
+
 // FormDecoder is the type used for decoding a form for use
 type FormDecoder interface {
 	Decode(interface{}, url.Values) error
@@ -18,8 +23,43 @@ type FormEncoder interface {
 
 var (
 	// DefaultFormDecoder of this package, which is configurable
-	DefaultFormDecoder FormDecoder = form.NewDecoder()
+	DefaultFormDecoder FormDecoder = NewFormDecoder()
 
 	// DefaultFormEncoder of this package, which is configurable
-	DefaultFormEncoder FormEncoder = form.NewEncoder()
+	DefaultFormEncoder FormEncoder = NewFormEncoder()
 )
+
+// NewFormDecoder returns a new form decoder configured with the `form` tag and, when built with Go 1.18+, support for
+// decoding Option fields of the basic types and time.Time, see `RegisterOptionDecoder`.
+func NewFormDecoder() *form.Decoder {
+	d := form.NewDecoder()
+	registerOptionDecoders(d)
+	return d
+}
+
+// NewFormEncoder returns a new form encoder configured with the `form` tag and, when built with Go 1.18+, support for
+// encoding Option fields of the basic types and time.Time, see `RegisterOptionEncoder`.
+func NewFormEncoder() *form.Encoder {
+	e := form.NewEncoder()
+	registerOptionEncoders(e)
+	return e
+}
+
+// formFieldName returns the name of the struct field from its `form` tag, or the field name if untagged, and false if
+// the field is unexported or ignored using "-".
+func formFieldName(field reflect.StructField) (string, bool) {
+	if field.PkgPath != "" {
+		return "", false
+	}
+	name := field.Tag.Get(formTagName)
+	if idx := strings.IndexByte(name, ','); idx != -1 {
+		name = name[:idx]
+	}
+	switch name {
+	case "-":
+		return "", false
+	case "":
+		return field.Name, true
+	}
+	return name, true
+}
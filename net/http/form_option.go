@@ -0,0 +1,10 @@
+//go:build !go1.18
+// +build !go1.18
+
+package httpext
+
+import "github.com/go-playground/form/v4"
+
+func registerOptionDecoders(_ *form.Decoder) {}
+
+func registerOptionEncoders(_ *form.Encoder) {}
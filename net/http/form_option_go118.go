@@ -0,0 +1,87 @@
+//go:build go1.18
+// +build go1.18
+
+package httpext
+
+import (
+	"time"
+
+	"github.com/go-playground/form/v4"
+	. "github.com/go-playground/pkg/v5/values/option"
+)
+
+// optionValue is the single field struct used to decode and encode the inner value of an Option using the same
+// decoder or encoder, and so the same conversions, as any other field.
+type optionValue[T any] struct {
+	V T `form:"v"`
+}
+
+// RegisterOptionDecoder registers `Option[T]` with the decoder so that an Option field decodes to None when absent and
+// to Some(value) when present, the value being converted exactly as a field of type `T` would be.
+//
+// `T` must be a type the decoder supports as a single field value such as a basic type, time.Time or a slice of them.
+func RegisterOptionDecoder[T any](d *form.Decoder) {
+	d.RegisterCustomTypeFunc(func(values []string) (interface{}, error) {
+		var ov optionValue[T]
+		if err := d.Decode(&ov, map[string][]string{"v": values}); err != nil {
+			return nil, err
+		}
+		return Some(ov.V), nil
+	}, Option[T]{})
+}
+
+// RegisterOptionEncoder registers `Option[T]` with the encoder so that a Some(value) field encodes exactly as a field
+// of type `T` would be and None produces no values.
+//
+// `T` must be a type the encoder supports as a single field value such as a basic type, time.Time or a slice of them.
+func RegisterOptionEncoder[T any](e *form.Encoder) {
+	e.RegisterCustomTypeFunc(func(x interface{}) ([]string, error) {
+		o := x.(Option[T])
+		if o.IsNone() {
+			return nil, nil
+		}
+		values, err := e.Encode(optionValue[T]{V: o.Unwrap()})
+		if err != nil {
+			return nil, err
+		}
+		return values["v"], nil
+	}, Option[T]{})
+}
+
+func registerOptionDecoders(d *form.Decoder) {
+	RegisterOptionDecoder[string](d)
+	RegisterOptionDecoder[bool](d)
+	RegisterOptionDecoder[int](d)
+	RegisterOptionDecoder[int8](d)
+	RegisterOptionDecoder[int16](d)
+	RegisterOptionDecoder[int32](d)
+	RegisterOptionDecoder[int64](d)
+	RegisterOptionDecoder[uint](d)
+	RegisterOptionDecoder[uint8](d)
+	RegisterOptionDecoder[uint16](d)
+	RegisterOptionDecoder[uint32](d)
+	RegisterOptionDecoder[uint64](d)
+	RegisterOptionDecoder[float32](d)
+	RegisterOptionDecoder[float64](d)
+	RegisterOptionDecoder[time.Time](d)
+	RegisterOptionDecoder[[]string](d)
+}
+
+func registerOptionEncoders(e *form.Encoder) {
+	RegisterOptionEncoder[string](e)
+	RegisterOptionEncoder[bool](e)
+	RegisterOptionEncoder[int](e)
+	RegisterOptionEncoder[int8](e)
+	RegisterOptionEncoder[int16](e)
+	RegisterOptionEncoder[int32](e)
+	RegisterOptionEncoder[int64](e)
+	RegisterOptionEncoder[uint](e)
+	RegisterOptionEncoder[uint8](e)
+	RegisterOptionEncoder[uint16](e)
+	RegisterOptionEncoder[uint32](e)
+	RegisterOptionEncoder[uint64](e)
+	RegisterOptionEncoder[float32](e)
+	RegisterOptionEncoder[float64](e)
+	RegisterOptionEncoder[time.Time](e)
+	RegisterOptionEncoder[[]string](e)
+}
@@ -0,0 +1,51 @@
+//go:build go1.18
+// +build go1.18
+
+package httpext
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	. "github.com/go-playground/assert/v2"
+	. "github.com/go-playground/pkg/v5/values/option"
+)
+
+func TestFormOption(t *testing.T) {
+	type Custom int
+
+	type Test struct {
+		Limit  Option[int]       `form:"limit"`
+		Name   Option[string]    `form:"name"`
+		Since  Option[time.Time] `form:"since"`
+		Tags   Option[[]string]  `form:"tags"`
+		Custom Option[Custom]    `form:"custom"`
+	}
+
+	decoder := NewFormDecoder()
+	RegisterOptionDecoder[Custom](decoder)
+	encoder := NewFormEncoder()
+	RegisterOptionEncoder[Custom](encoder)
+
+	var test Test
+	err := decoder.Decode(&test, url.Values{"limit": {"0"}, "tags": {"a", "b"}, "custom": {"7"}})
+	Equal(t, err, nil)
+	Equal(t, test.Limit, Some(0))
+	Equal(t, test.Name, None[string]())
+	Equal(t, test.Since, None[time.Time]())
+	Equal(t, test.Tags, Some([]string{"a", "b"}))
+	Equal(t, test.Custom, Some(Custom(7)))
+
+	var invalid Test
+	err = decoder.Decode(&invalid, url.Values{"limit": {"abc"}})
+	NotEqual(t, err, nil)
+
+	values, err := encoder.Encode(test)
+	Equal(t, err, nil)
+	Equal(t, values["limit"], []string{"0"})
+	Equal(t, values["tags"], []string{"a", "b"})
+	Equal(t, values["custom"], []string{"7"})
+	Equal(t, len(values["name"]), 0)
+	Equal(t, url.Values(values).Encode(), "custom=7&limit=0&tags=a&tags=b")
+}
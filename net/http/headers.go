@@ -21,6 +21,8 @@ const (
 	ContentLanguage               string = "Content-Language"
 	ContentLocation               string = "Content-Location"
 	ContentRange                  string = "Content-Range"
+	ContentSecurityPolicy         string = "Content-Security-Policy"
+	ContentSecurityPolicyReport   string = "Content-Security-Policy-Report-Only"
 	Date                          string = "Date"
 	DeltaBase                     string = "Delta-Base"
 	ETag                          string = "ETag"
@@ -32,6 +34,7 @@ const (
 	IfNoneMatch                   string = "If-None-Match"
 	IfRange                       string = "If-Range"
 	IfUnmodifiedSince             string = "If-Unmodified-Since"
+	IdempotencyKey                string = "Idempotency-Key"
 	KeepAlive                     string = "Keep-Alive"
 	LastModified                  string = "Last-Modified"
 	Link                          string = "Link"
@@ -42,6 +45,7 @@ const (
 	RetryAfter                    string = "Retry-After"
 	Referer                       string = "Referer"
 	Server                        string = "Server"
+	ServerTiming                  string = "Server-Timing"
 	SetCookie                     string = "Set-Cookie"
 	StrictTransportSecurity       string = "Strict-Transport-Security"
 	Trailer                       string = "Trailer"
@@ -61,6 +65,12 @@ const (
 	XFrameOptions                 string = "X-Frame-Options"
 	XXSSProtection                string = "X-XSS-Protection"
 	XDNSPrefetchControl           string = "X-DNS-Prefetch-Control"
+	XAccelBuffering               string = "X-Accel-Buffering"
+	XIdempotencyKey               string = "X-Idempotency-Key"
+	XRequestID                    string = "X-Request-Id"
+	XRequestAttempt               string = "X-Request-Attempt"
+	XRequestTimeout               string = "X-Request-Timeout"
+	GRPCTimeout                   string = "Grpc-Timeout"
 	Allow                         string = "Allow"
 	Origin                        string = "Origin"
 	AccessControlAllowOrigin      string = "Access-Control-Allow-Origin"
@@ -63,8 +63,10 @@ func AcceptedLanguages(r *http.Request) (languages []string) {
 
 // Attachment is a helper method for returning an attachment file
 // to be downloaded, if you with to open inline see function Inline
+//
+// The filename is safely quoted and encoded as described by FormatContentDisposition.
 func Attachment(w http.ResponseWriter, r io.Reader, filename string) (err error) {
-	w.Header().Set(ContentDisposition, "attachment;filename="+filename)
+	w.Header().Set(ContentDisposition, FormatContentDisposition(DispositionAttachment, filename))
 	w.Header().Set(ContentType, detectContentType(filename))
 	w.WriteHeader(http.StatusOK)
 	_, err = io.Copy(w, r)
@@ -73,8 +75,10 @@ func Attachment(w http.ResponseWriter, r io.Reader, filename string) (err error)
 
 // Inline is a helper method for returning a file inline to
 // be rendered/opened by the browser
+//
+// The filename is safely quoted and encoded as described by FormatContentDisposition.
 func Inline(w http.ResponseWriter, r io.Reader, filename string) (err error) {
-	w.Header().Set(ContentDisposition, "inline;filename="+filename)
+	w.Header().Set(ContentDisposition, FormatContentDisposition(DispositionInline, filename))
 	w.Header().Set(ContentType, detectContentType(filename))
 	w.WriteHeader(http.StatusOK)
 	_, err = io.Copy(w, r)
@@ -197,6 +201,9 @@ func DecodeForm(r *http.Request, qp QueryParamsOption, v interface{}) (err error
 //
 // The Content-Type and http method are not checked.
 //
+// Uploaded files are bound, by form tag name, to top level struct fields of type *multipart.FileHeader,
+// []*multipart.FileHeader, UploadedFile, *UploadedFile and []UploadedFile.
+//
 // NOTE: when includeQueryParams=true query params will be parsed and included eg. route /user?test=true 'test'
 // is added to parsed MultipartForm.
 func DecodeMultipartForm(r *http.Request, qp QueryParamsOption, maxMemory int64, v interface{}) (err error) {
@@ -207,6 +214,9 @@ func DecodeMultipartForm(r *http.Request, qp QueryParamsOption, maxMemory int64,
 		case NoQueryParams:
 			err = DefaultFormDecoder.Decode(v, r.MultipartForm.Value)
 		}
+		if err == nil {
+			err = bindMultipartFiles(r.MultipartForm, v)
+		}
 	}
 	return
 }
@@ -238,7 +248,7 @@ func decodeJSON(headers http.Header, body io.Reader, qp QueryParamsOption, value
 		}()
 		body = gzr
 	}
-	err = json.NewDecoder(ioext.LimitReader(body, maxMemory)).Decode(v)
+	err = json.NewDecoder(contentTypeReader(headers.Get(ContentType), ioext.LimitReader(body, maxMemory))).Decode(v)
 	if qp == QueryParams && err == nil {
 		err = decodeQueryParams(values, v)
 	}
@@ -272,13 +282,44 @@ func decodeXML(headers http.Header, body io.Reader, qp QueryParamsOption, values
 		}()
 		body = gzr
 	}
-	err = xml.NewDecoder(ioext.LimitReader(body, maxMemory)).Decode(v)
+	var reader io.Reader = ioext.LimitReader(body, maxMemory)
+	var dec *xml.Decoder
+	// the Content-Type charset takes precedence over the XML declared encoding as described by RFC 7303 section 3.2,
+	// falling back to the declared encoding when unsupported
+	if charset := contentTypeCharset(headers.Get(ContentType)); charset != "" {
+		if transcoded, cerr := CharsetReader(charset, reader); cerr == nil {
+			dec = xml.NewDecoder(transcoded)
+			dec.CharsetReader = func(_ string, input io.Reader) (io.Reader, error) { return input, nil }
+		}
+	}
+	if dec == nil {
+		dec = xml.NewDecoder(reader)
+		dec.CharsetReader = CharsetReader
+	}
+	err = dec.Decode(v)
 	if qp == QueryParams && err == nil {
 		err = decodeQueryParams(values, v)
 	}
 	return
 }
 
+// decodeText decodes the text body, transcoded to UTF-8 from its charset, into v which must be a *string or *[]byte.
+func decodeText(headers http.Header, body io.Reader, maxMemory int64, v interface{}) (err error) {
+	reader := contentTypeReader(headers.Get(ContentType), ioext.LimitReader(body, maxMemory))
+	switch t := v.(type) {
+	case *string:
+		var b []byte
+		if b, err = io.ReadAll(reader); err == nil {
+			*t = string(b)
+		}
+	case *[]byte:
+		*t, err = io.ReadAll(reader)
+	default:
+		err = errors.New("unsupported type for text content, must be *string or *[]byte")
+	}
+	return
+}
+
 // DecodeQueryParams takes the URL Query params flag.
 func DecodeQueryParams(r *http.Request, v interface{}) (err error) {
 	return decodeQueryParams(r.URL.Query(), v)
@@ -328,6 +369,9 @@ func Decode(r *http.Request, qp QueryParamsOption, maxMemory int64, v interface{
 // DecodeResponseAny takes the response and attempts to discover its content type via
 // the http headers and then decode the request body into the provided type.
 //
+// The body is transcoded to UTF-8 from the Content-Type charset, see `RegisterCharset`, and "text/plain" bodies
+// may be decoded into a *string or *[]byte.
+//
 // Example if header was "application/json" would decode using
 // json.NewDecoder(ioext.LimitReader(r.Body, maxBytes)).Decode(v).
 func DecodeResponseAny(r *http.Response, maxMemory bytesext.Bytes, v interface{}) (err error) {
@@ -340,6 +384,8 @@ func DecodeResponseAny(r *http.Response, maxMemory bytesext.Bytes, v interface{}
 		err = decodeJSON(r.Header, r.Body, NoQueryParams, nil, maxMemory, v)
 	case nakedApplicationXML:
 		err = decodeXML(r.Header, r.Body, NoQueryParams, nil, maxMemory, v)
+	case TextPlainNoCharset:
+		err = decodeText(r.Header, r.Body, maxMemory, v)
 	default:
 		err = errors.New("unsupported content type")
 	}
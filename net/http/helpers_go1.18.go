@@ -18,6 +18,9 @@ import (
 // DecodeResponse takes the response and attempts to discover its content type via
 // the http headers and then decode the request body into the provided type.
 //
+// The body is transcoded to UTF-8 from the Content-Type charset, see `RegisterCharset`, and "text/plain" bodies
+// may be decoded into a string or []byte.
+//
 // Example if header was "application/json" would decode using
 // json.NewDecoder(ioext.LimitReader(r.Body, maxBytes)).Decode(v).
 func DecodeResponse[T any](r *http.Response, maxMemory bytesext.Bytes) (result T, err error) {
@@ -30,6 +33,8 @@ func DecodeResponse[T any](r *http.Response, maxMemory bytesext.Bytes) (result T
 		err = decodeJSON(r.Header, r.Body, NoQueryParams, nil, maxMemory, &result)
 	case nakedApplicationXML:
 		err = decodeXML(r.Header, r.Body, NoQueryParams, nil, maxMemory, &result)
+	case TextPlainNoCharset:
+		err = decodeText(r.Header, r.Body, maxMemory, &result)
 	default:
 		err = errors.New("unsupported content type")
 	}
@@ -52,3 +57,15 @@ func HasRetryAfter(headers http.Header) Option[time.Duration] {
 	}
 	return None[time.Duration]()
 }
+
+// HasFilename parses the Content-Disposition header and returns the server suggested filename if present.
+//
+// Any directory components of the filename are stripped so that it is safe to use as a local file name.
+func HasFilename(headers http.Header) Option[string] {
+	if cd := headers.Get(ContentDisposition); cd != "" {
+		if _, filename, err := ParseContentDisposition(cd); err == nil && filename != "" {
+			return Some(filename)
+		}
+	}
+	return None[string]()
+}
@@ -66,19 +66,19 @@ func TestAttachment(t *testing.T) {
 	}{
 		{
 			code:        http.StatusOK,
-			disposition: "attachment;filename=README.md",
+			disposition: `attachment; filename="README.md"`,
 			typ:         TextMarkdown,
 			url:         "/dl",
 		},
 		{
 			code:        http.StatusOK,
-			disposition: "attachment;filename=readme",
+			disposition: `attachment; filename="readme"`,
 			typ:         ApplicationOctetStream,
 			url:         "/dl-unknown-type",
 		},
 		{
 			code:        http.StatusOK,
-			disposition: "attachment;filename=logo.png",
+			disposition: `attachment; filename="logo.png"`,
 			typ:         ImagePNG,
 			url:         "/dl-fake-png",
 		},
@@ -129,13 +129,13 @@ func TestInline(t *testing.T) {
 	}{
 		{
 			code:        http.StatusOK,
-			disposition: "inline;filename=README.md",
+			disposition: `inline; filename="README.md"`,
 			typ:         TextMarkdown,
 			url:         "/dl-inline",
 		},
 		{
 			code:        http.StatusOK,
-			disposition: "inline;filename=readme",
+			disposition: `inline; filename="readme"`,
 			typ:         ApplicationOctetStream,
 			url:         "/dl-unknown-type-inline",
 		},
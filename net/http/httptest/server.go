@@ -0,0 +1,247 @@
+package httptestext
+
+import (
+	"bytes"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// Fault represents a scripted network fault to inject instead of, or part way through, a response.
+type Fault uint8
+
+const (
+	// NoFault writes the scripted response normally.
+	NoFault Fault = iota
+
+	// ConnectionReset reads the request and then resets the connection without writing any response.
+	ConnectionReset
+
+	// Hang reads the request and never responds, until the client gives up or the server is closed.
+	Hang
+
+	// TruncatedBody writes the headers, with the Content-Length of the full body, followed by only the first
+	// `TruncateAfter` bytes of the body before closing the connection.
+	TruncatedBody
+
+	// PartialBody writes the headers followed by only the first `TruncateAfter` bytes of the body and then hangs,
+	// until the client gives up or the server is closed.
+	PartialBody
+)
+
+// Response is a single scripted response.
+type Response struct {
+	// Status is the status code to write, defaults to 200 if not set.
+	Status int
+
+	// Header contains the headers to write.
+	Header http.Header
+
+	// Body is the response body to write.
+	Body []byte
+
+	// Delay is how long to wait before writing anything, including faults.
+	Delay time.Duration
+
+	// Fault is the optional fault to inject.
+	Fault Fault
+
+	// TruncateAfter is the number of body bytes to write for the TruncatedBody and PartialBody faults.
+	TruncateAfter int
+}
+
+// RecordedRequest is a request received by the Server.
+type RecordedRequest struct {
+	// Method is the HTTP method of the request.
+	Method string
+
+	// URL is the request URL.
+	URL *url.URL
+
+	// Header contains the request headers.
+	Header http.Header
+
+	// Body is the full request body.
+	Body []byte
+
+	// Attempt is the zero based index of the request for its path, which is also the index of the scripted
+	// response it received.
+	Attempt int
+
+	// Received is when the request was received.
+	Received time.Time
+}
+
+// Server is an httptest.Server which replies to each path with a scripted sequence of responses, including injected
+// faults, and records every request it receives so that client retry, backoff and body draining behaviour can be
+// verified entirely offline.
+//
+// Once a paths sequence is exhausted its last response is repeated. Requests for paths without a script receive a
+// 404 Not Found.
+type Server struct {
+	*httptest.Server
+	mu       sync.Mutex
+	scripts  map[string][]Response
+	requests []RecordedRequest
+	closed   chan struct{}
+	once     sync.Once
+}
+
+// NewServer starts and returns a new Server. The caller should call Close when finished, to shut it down.
+func NewServer() *Server {
+	s := &Server{
+		scripts: make(map[string][]Response),
+		closed:  make(chan struct{}),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Script appends the provided responses to the sequence of responses for the provided path.
+func (s *Server) Script(path string, responses ...Response) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.scripts[path] = append(s.scripts[path], responses...)
+	return s
+}
+
+// Requests returns a copy of all requests received, in the order received.
+func (s *Server) Requests() []RecordedRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]RecordedRequest(nil), s.requests...)
+}
+
+// RequestsFor returns a copy of all requests received for the provided path, in the order received.
+func (s *Server) RequestsFor(path string) (requests []RecordedRequest) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, r := range s.requests {
+		if r.URL.Path == path {
+			requests = append(requests, r)
+		}
+	}
+	return
+}
+
+// Close releases any hanging requests, shuts down the server and blocks until all outstanding requests on this
+// server have completed.
+func (s *Server) Close() {
+	s.once.Do(func() {
+		close(s.closed)
+	})
+	s.Server.Close()
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	s.mu.Lock()
+	var attempt int
+	for _, req := range s.requests {
+		if req.URL.Path == r.URL.Path {
+			attempt++
+		}
+	}
+	s.requests = append(s.requests, RecordedRequest{
+		Method:   r.Method,
+		URL:      r.URL,
+		Header:   r.Header.Clone(),
+		Body:     body,
+		Attempt:  attempt,
+		Received: time.Now(),
+	})
+	script, ok := s.scripts[r.URL.Path]
+	s.mu.Unlock()
+
+	if !ok || len(script) == 0 {
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+	if attempt >= len(script) {
+		attempt = len(script) - 1
+	}
+	resp := script[attempt]
+
+	if resp.Delay > 0 && !s.wait(r, resp.Delay) {
+		return
+	}
+
+	switch resp.Fault {
+	case ConnectionReset:
+		s.reset(w)
+		return
+	case Hang:
+		s.wait(r, 0)
+		return
+	}
+
+	for k, v := range resp.Header {
+		w.Header()[k] = append([]string(nil), v...)
+	}
+	status := resp.Status
+	if status == 0 {
+		status = http.StatusOK
+	}
+
+	switch resp.Fault {
+	case TruncatedBody, PartialBody:
+		n := resp.TruncateAfter
+		if n > len(resp.Body) {
+			n = len(resp.Body)
+		}
+		w.Header().Set("Content-Length", strconv.Itoa(len(resp.Body)))
+		w.WriteHeader(status)
+		_, _ = io.Copy(w, bytes.NewReader(resp.Body[:n]))
+		if f, ok := w.(http.Flusher); ok {
+			f.Flush()
+		}
+		if resp.Fault == PartialBody {
+			s.wait(r, 0)
+		}
+		// abort the response, closing the connection, without logging
+		panic(http.ErrAbortHandler)
+	default:
+		w.WriteHeader(status)
+		_, _ = w.Write(resp.Body)
+	}
+}
+
+// wait blocks for the provided duration, or indefinitely if 0, returning false if the request was cancelled or
+// the server closed first.
+func (s *Server) wait(r *http.Request, d time.Duration) bool {
+	var timer <-chan time.Time
+	if d > 0 {
+		t := time.NewTimer(d)
+		defer t.Stop()
+		timer = t.C
+	}
+	select {
+	case <-timer:
+		return true
+	case <-r.Context().Done():
+	case <-s.closed:
+	}
+	return false
+}
+
+func (s *Server) reset(w http.ResponseWriter) {
+	hj, ok := w.(http.Hijacker)
+	if !ok {
+		panic(http.ErrAbortHandler)
+	}
+	conn, _, err := hj.Hijack()
+	if err != nil {
+		return
+	}
+	if tcp, ok := conn.(*net.TCPConn); ok {
+		// discard any unsent data and send RST instead of FIN
+		_ = tcp.SetLinger(0)
+	}
+	_ = conn.Close()
+}
@@ -0,0 +1,140 @@
+//go:build go1.18
+// +build go1.18
+
+package httptestext
+
+import (
+	"context"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/go-playground/assert/v2"
+	httpext "github.com/go-playground/pkg/v5/net/http"
+)
+
+type test struct {
+	Name string `json:"name"`
+}
+
+func TestServer(t *testing.T) {
+	server := NewServer().
+		Script("/retry",
+			Response{Status: http.StatusServiceUnavailable},
+			Response{Status: http.StatusTooManyRequests, Header: http.Header{httpext.RetryAfter: []string{"0"}}},
+			Response{Status: http.StatusOK, Header: http.Header{httpext.ContentType: []string{httpext.ApplicationJSON}}, Body: []byte(`{"name":"test"}`)},
+		).
+		Script("/reset",
+			Response{Fault: ConnectionReset},
+			Response{Status: http.StatusOK, Header: http.Header{httpext.ContentType: []string{httpext.ApplicationJSON}}, Body: []byte(`{"name":"reset"}`)},
+		).
+		Script("/hang",
+			Response{Fault: Hang},
+			Response{Delay: 10 * time.Millisecond, Status: http.StatusOK, Header: http.Header{httpext.ContentType: []string{httpext.ApplicationJSON}}, Body: []byte(`{"name":"hang"}`)},
+		).
+		Script("/truncated",
+			Response{Fault: TruncatedBody, TruncateAfter: 5, Status: http.StatusOK, Header: http.Header{httpext.ContentType: []string{httpext.ApplicationJSON}}, Body: []byte(`{"name":"truncated"}`)},
+			Response{Status: http.StatusOK, Header: http.Header{httpext.ContentType: []string{httpext.ApplicationJSON}}, Body: []byte(`{"name":"truncated"}`)},
+		)
+	defer server.Close()
+
+	retryer := httpext.NewRetryer().
+		IsRetryableFn(func(_ context.Context, _ error) bool { return true }).
+		Backoff(nil).
+		Timeout(time.Second)
+
+	tests := []struct {
+		name     string
+		path     string
+		retryer  httpext.Retryer
+		expected string
+		attempts int
+	}{
+		{
+			name:     "retry-status-codes",
+			path:     "/retry",
+			retryer:  retryer,
+			expected: "test",
+			attempts: 3,
+		},
+		{
+			name:     "connection-reset",
+			path:     "/reset",
+			retryer:  retryer,
+			expected: "reset",
+			attempts: 2,
+		},
+		{
+			name:     "hang",
+			path:     "/hang",
+			retryer:  retryer.Timeout(100 * time.Millisecond),
+			expected: "hang",
+			attempts: 2,
+		},
+		{
+			name:     "truncated-body",
+			path:     "/truncated",
+			retryer:  retryer,
+			expected: "truncated",
+			attempts: 2,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var result test
+			err := tc.retryer.Do(context.Background(), httpext.NewRequestBuilder(http.MethodGet, server.URL+tc.path).NoBody(), &result, http.StatusOK)
+			Equal(t, err, nil)
+			Equal(t, result.Name, tc.expected)
+
+			requests := server.RequestsFor(tc.path)
+			Equal(t, len(requests), tc.attempts)
+			for i, r := range requests {
+				Equal(t, r.Attempt, i)
+				Equal(t, r.Method, http.MethodGet)
+			}
+		})
+	}
+}
+
+func TestServerRecording(t *testing.T) {
+	server := NewServer().Script("/echo", Response{Status: http.StatusAccepted})
+	defer server.Close()
+
+	resp := httpext.NewRetryer().DoResponse(context.Background(), httpext.NewRequestBuilder(http.MethodPost, server.URL+"/echo").Header("X-Test", "true").Bytes(httpext.TextPlain, []byte("payload")), http.StatusAccepted)
+	Equal(t, resp.IsOk(), true)
+	_ = resp.Unwrap().Body.Close()
+
+	resp = httpext.NewRetryer().DoResponse(context.Background(), httpext.NewRequestBuilder(http.MethodGet, server.URL+"/unscripted").NoBody())
+	Equal(t, resp.IsOk(), true)
+	Equal(t, resp.Unwrap().StatusCode, http.StatusNotFound)
+	_ = resp.Unwrap().Body.Close()
+
+	requests := server.Requests()
+	Equal(t, len(requests), 2)
+	Equal(t, requests[0].Method, http.MethodPost)
+	Equal(t, requests[0].Header.Get("X-Test"), "true")
+	Equal(t, string(requests[0].Body), "payload")
+	Equal(t, requests[1].URL.Path, "/unscripted")
+}
+
+func TestServerCloseReleasesHangs(t *testing.T) {
+	server := NewServer().Script("/partial", Response{Fault: PartialBody, TruncateAfter: 2, Body: []byte("partial")})
+
+	resp, err := http.Get(server.URL + "/partial")
+	Equal(t, err, nil)
+	defer resp.Body.Close()
+	Equal(t, resp.StatusCode, http.StatusOK)
+
+	done := make(chan struct{})
+	go func() {
+		server.Close()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(5 * time.Second):
+		t.Fatal("server close blocked by hanging request")
+	}
+}
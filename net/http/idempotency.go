@@ -0,0 +1,97 @@
+//go:build go1.18
+// +build go1.18
+
+package httpext
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+)
+
+// IdempotencyMode is used to set how the `Retryer` treats ambiguous network errors for non-idempotent requests.
+type IdempotencyMode uint8
+
+const (
+	// IdempotentOnly will only retry ambiguous network errors, where the server may have processed the request, for
+	// idempotent methods (GET, HEAD, PUT, DELETE, OPTIONS, TRACE) or requests containing an `Idempotency-Key` header.
+	IdempotentOnly IdempotencyMode = iota
+
+	// IdempotencyIgnored will retry ambiguous network errors for all requests regardless of method.
+	IdempotencyIgnored
+)
+
+// IdempotencyKeyFn is a function used to generate an Idempotency-Key header value.
+type IdempotencyKeyFn func() string
+
+// ErrNonIdempotentRequest is returned when an ambiguous network error was encountered for a non-idempotent request
+// which was not retried as doing so could result in the request being processed more than once.
+type ErrNonIdempotentRequest struct {
+	// Method is the HTTP method of the request.
+	Method string
+
+	// Err is the original network error encountered.
+	Err error
+}
+
+// Error returns the error message for the non-idempotent request.
+func (e ErrNonIdempotentRequest) Error() string {
+	return "not retrying non-idempotent " + e.Method + " request: " + e.Err.Error()
+}
+
+// Unwrap returns the original network error.
+func (e ErrNonIdempotentRequest) Unwrap() error {
+	return e.Err
+}
+
+// NewIdempotencyKey returns a new random, UUID v4 formatted, key suitable for use as an Idempotency-Key header value.
+func NewIdempotencyKey() string {
+	var b [16]byte
+	_, _ = rand.Read(b[:])
+	b[6] = (b[6] & 0x0f) | 0x40
+	b[8] = (b[8] & 0x3f) | 0x80
+
+	var buf [36]byte
+	hex.Encode(buf[0:8], b[0:4])
+	buf[8] = '-'
+	hex.Encode(buf[9:13], b[4:6])
+	buf[13] = '-'
+	hex.Encode(buf[14:18], b[6:8])
+	buf[18] = '-'
+	hex.Encode(buf[19:23], b[8:10])
+	buf[23] = '-'
+	hex.Encode(buf[24:], b[10:])
+	return string(buf[:])
+}
+
+// IsIdempotentMethod returns true if the provided HTTP method is defined as idempotent by RFC 9110 section 9.2.2.
+func IsIdempotentMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return false
+}
+
+// isIdempotentRequest returns true if the request is idempotent by method or has an Idempotency-Key header.
+func isIdempotentRequest(req *http.Request) bool {
+	return IsIdempotentMethod(req.Method) || req.Header.Get(IdempotencyKey) != "" || req.Header.Get(XIdempotencyKey) != ""
+}
+
+// isUnprocessedError returns true if the error indicates the request was never processed by the server, and so is
+// always safe to retry, because it occurred while establishing a connection or the server closed the idle connection
+// before handling it.
+//
+// An HTTP/2 GOAWAY error is NOT included as it is only returned for streams the server received and may have
+// processed, those it did not are already retried by the transport.
+func isUnprocessedError(err error) bool {
+	var opErr *net.OpError
+	if errors.As(err, &opErr) && opErr.Op == "dial" {
+		return true
+	}
+	// see the notes of `errorsext.IsRetryableHTTP`
+	return strings.Contains(err.Error(), "http: server closed idle connection")
+}
@@ -0,0 +1,169 @@
+//go:build go1.18
+// +build go1.18
+
+package httpext
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	. "github.com/go-playground/assert/v2"
+	errorsext "github.com/go-playground/pkg/v5/errors"
+	. "github.com/go-playground/pkg/v5/values/result"
+)
+
+func TestRetryerIdempotency(t *testing.T) {
+	var (
+		mu   sync.Mutex
+		keys []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys = append(keys, r.Header.Get(IdempotencyKey))
+		mu.Unlock()
+
+		// simulate the connection dropping after the server received the request
+		conn, _, err := w.(http.Hijacker).Hijack()
+		Equal(t, err, nil)
+		_ = conn.Close()
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name        string
+		method      string
+		retryer     func(r Retryer) Retryer
+		header      string
+		retried     bool
+		expectedKey bool
+	}{
+		{
+			name:    "get-retried",
+			method:  http.MethodGet,
+			retried: true,
+		},
+		{
+			name:    "post-not-retried",
+			method:  http.MethodPost,
+			retried: false,
+		},
+		{
+			name:        "post-with-key-retried",
+			method:      http.MethodPost,
+			header:      "my-key",
+			retried:     true,
+			expectedKey: true,
+		},
+		{
+			name:   "post-generated-key-retried",
+			method: http.MethodPost,
+			retryer: func(r Retryer) Retryer {
+				return r.IdempotencyKeyFn(NewIdempotencyKey)
+			},
+			retried:     true,
+			expectedKey: true,
+		},
+		{
+			name:   "patch-idempotency-ignored",
+			method: http.MethodPatch,
+			retryer: func(r Retryer) Retryer {
+				return r.Idempotency(IdempotencyIgnored)
+			},
+			retried: true,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			mu.Lock()
+			keys = nil
+			mu.Unlock()
+
+			var retries int
+			retryer := NewRetryer().
+				IsRetryableFn(func(_ context.Context, _ error) bool { return true }).
+				MaxAttempts(errorsext.MaxAttempts, 3).
+				Backoff(func(_ context.Context, _ int, _ error) { retries++ })
+			if tc.retryer != nil {
+				retryer = tc.retryer(retryer)
+			}
+
+			result := retryer.DoResponse(context.Background(), func(ctx context.Context) Result[*http.Request, error] {
+				req, err := http.NewRequestWithContext(ctx, tc.method, server.URL, nil)
+				if err != nil {
+					return Err[*http.Request, error](err)
+				}
+				if tc.header != "" {
+					req.Header.Set(IdempotencyKey, tc.header)
+				}
+				return Ok[*http.Request, error](req)
+			})
+			Equal(t, result.IsErr(), true)
+
+			var nie ErrNonIdempotentRequest
+			Equal(t, errors.As(result.Err(), &nie), !tc.retried)
+			Equal(t, retries > 0, tc.retried)
+
+			mu.Lock()
+			defer mu.Unlock()
+			NotEqual(t, len(keys), 0)
+			for _, key := range keys {
+				Equal(t, key, keys[0])
+			}
+			Equal(t, keys[0] != "", tc.expectedKey)
+		})
+	}
+}
+
+func TestNewIdempotencyKey(t *testing.T) {
+	key := NewIdempotencyKey()
+	Equal(t, len(key), 36)
+	Equal(t, key[14], byte('4'))
+	NotEqual(t, key, NewIdempotencyKey())
+}
+
+func TestIsUnprocessedError(t *testing.T) {
+	Equal(t, isUnprocessedError(&net.OpError{Op: "dial", Err: errors.New("connection refused")}), true)
+	Equal(t, isUnprocessedError(fmt.Errorf("Post: %w", errors.New("http: server closed idle connection"))), true)
+	Equal(t, isUnprocessedError(errors.New("http2: server sent GOAWAY and closed the connection")), false)
+	Equal(t, isUnprocessedError(&net.OpError{Op: "read", Err: errors.New("connection reset by peer")}), false)
+}
+
+type roundTripperFn func(req *http.Request) (*http.Response, error)
+
+func (fn roundTripperFn) RoundTrip(req *http.Request) (*http.Response, error) {
+	return fn(req)
+}
+
+func TestRetryerIdempotencyGoAway(t *testing.T) {
+	// as returned by the http2 transport for streams at or below the GOAWAY LastStreamID
+	goAway := errors.New(`http2: server sent GOAWAY and closed the connection; LastStreamID=1, ErrCode=NO_ERROR, debug=""`)
+	client := &http.Client{Transport: roundTripperFn(func(req *http.Request) (*http.Response, error) {
+		return nil, goAway
+	})}
+
+	var retries int
+	result := NewRetryer().
+		Client(client).
+		Backoff(func(_ context.Context, _ int, _ error) { retries++ }).
+		DoResponse(context.Background(), func(ctx context.Context) Result[*http.Request, error] {
+			req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://example.com", nil)
+			if err != nil {
+				return Err[*http.Request, error](err)
+			}
+			return Ok[*http.Request, error](req)
+		})
+	Equal(t, result.IsErr(), true)
+
+	var nie ErrNonIdempotentRequest
+	Equal(t, errors.As(result.Err(), &nie), true)
+	Equal(t, errors.Is(result.Err(), goAway), true)
+	Equal(t, retries, 0)
+}
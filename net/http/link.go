@@ -0,0 +1,201 @@
+package httpext
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// LinkValue represents a single link-value of an RFC 8288 Link header.
+type LinkValue struct {
+	// URL is the target URI-Reference of the link, which may be relative.
+	URL string
+
+	// Rel is the space separated relation types of the link eg. "next" or "prev first".
+	Rel string
+
+	// Anchor is the optional context URI of the link.
+	Anchor string
+
+	// Params contains any other target attributes keyed by their lower-cased name eg. "title" or "type".
+	Params map[string]string
+}
+
+// HasRel returns true if the link has the provided relation type, compared case-insensitively.
+func (l LinkValue) HasRel(rel string) bool {
+	for _, r := range strings.Fields(l.Rel) {
+		if strings.EqualFold(r, rel) {
+			return true
+		}
+	}
+	return false
+}
+
+// String returns the link formatted as an RFC 8288 link-value.
+func (l LinkValue) String() string {
+	var sb strings.Builder
+	sb.WriteByte('<')
+	sb.WriteString(l.URL)
+	sb.WriteByte('>')
+	if l.Rel != "" {
+		writeLinkParam(&sb, "rel", l.Rel)
+	}
+	if l.Anchor != "" {
+		writeLinkParam(&sb, "anchor", l.Anchor)
+	}
+	keys := make([]string, 0, len(l.Params))
+	for k := range l.Params {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		writeLinkParam(&sb, k, l.Params[k])
+	}
+	return sb.String()
+}
+
+func writeLinkParam(sb *strings.Builder, name, value string) {
+	sb.WriteString("; ")
+	sb.WriteString(name)
+	if strings.HasSuffix(name, "*") {
+		// RFC 8187 ext-value is never quoted
+		sb.WriteByte('=')
+		sb.WriteString(value)
+		return
+	}
+	sb.WriteString(`="`)
+	for i := 0; i < len(value); i++ {
+		if c := value[i]; c == '"' || c == '\\' {
+			sb.WriteByte('\\')
+		}
+		sb.WriteByte(value[i])
+	}
+	sb.WriteByte('"')
+}
+
+// FormatLinks returns the provided links formatted as an RFC 8288 Link header value.
+func FormatLinks(links ...LinkValue) string {
+	values := make([]string, len(links))
+	for i, l := range links {
+		values[i] = l.String()
+	}
+	return strings.Join(values, ", ")
+}
+
+// ParseLinks parses all Link headers into their individual link-values as described by RFC 8288.
+//
+// Invalid link-values are skipped.
+func ParseLinks(headers http.Header) (links []LinkValue) {
+	for _, value := range headers.Values(Link) {
+		links = append(links, ParseLink(value)...)
+	}
+	return
+}
+
+// ParseLink parses a single Link header value into its individual link-values as described by RFC 8288.
+//
+// Invalid link-values are skipped.
+func ParseLink(s string) (links []LinkValue) {
+	for {
+		s = strings.TrimLeft(s, " \t,")
+		if s == "" {
+			return
+		}
+		if s[0] != '<' {
+			s = skipLinkValue(s)
+			continue
+		}
+		end := strings.IndexByte(s, '>')
+		if end == -1 {
+			return
+		}
+		link := LinkValue{URL: strings.TrimSpace(s[1:end])}
+		s = s[end+1:]
+
+		for {
+			s = strings.TrimLeft(s, " \t")
+			if s == "" || s[0] != ';' {
+				break
+			}
+			s = strings.TrimLeft(s[1:], " \t")
+
+			var name, value string
+			idx := strings.IndexAny(s, "=;, \t")
+			if idx == -1 {
+				name, s = s, ""
+			} else {
+				name, s = s[:idx], strings.TrimLeft(s[idx:], " \t")
+				if s != "" && s[0] == '=' {
+					value, s = parseLinkParamValue(strings.TrimLeft(s[1:], " \t"))
+				}
+			}
+			if name == "" {
+				continue
+			}
+			name = strings.ToLower(name)
+			switch name {
+			case "rel":
+				// only the first occurrence of rel is used as described by RFC 8288 section 3.3
+				if link.Rel == "" {
+					link.Rel = value
+				}
+			case "anchor":
+				link.Anchor = value
+			default:
+				if link.Params == nil {
+					link.Params = make(map[string]string)
+				}
+				if _, ok := link.Params[name]; !ok {
+					link.Params[name] = value
+				}
+			}
+		}
+		links = append(links, link)
+	}
+}
+
+// parseLinkParamValue parses a token or quoted-string param value returning the unquoted value and remainder.
+func parseLinkParamValue(s string) (value, remainder string) {
+	if s == "" || s[0] != '"' {
+		idx := strings.IndexAny(s, ";,")
+		if idx == -1 {
+			return strings.TrimSpace(s), ""
+		}
+		return strings.TrimSpace(s[:idx]), s[idx:]
+	}
+	var sb strings.Builder
+	for i := 1; i < len(s); i++ {
+		switch c := s[i]; c {
+		case '\\':
+			if i+1 < len(s) {
+				i++
+				sb.WriteByte(s[i])
+			}
+		case '"':
+			return sb.String(), s[i+1:]
+		default:
+			sb.WriteByte(c)
+		}
+	}
+	return sb.String(), ""
+}
+
+// skipLinkValue skips to the next link-value in the header, respecting quoted-strings.
+func skipLinkValue(s string) string {
+	var quoted bool
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			if quoted {
+				i++
+			}
+		case '"':
+			quoted = !quoted
+		case ',':
+			if !quoted {
+				return s[i+1:]
+			}
+		}
+	}
+	return ""
+}
@@ -0,0 +1,67 @@
+package httpext
+
+import (
+	"net/http"
+	"testing"
+
+	. "github.com/go-playground/assert/v2"
+)
+
+func TestParseLink(t *testing.T) {
+	tests := []struct {
+		name     string
+		value    string
+		expected []LinkValue
+	}{
+		{
+			name:  "github",
+			value: `<https://api.github.com/repositories/1/issues?page=2>; rel="next", <https://api.github.com/repositories/1/issues?page=5>; rel="last"`,
+			expected: []LinkValue{
+				{URL: "https://api.github.com/repositories/1/issues?page=2", Rel: "next"},
+				{URL: "https://api.github.com/repositories/1/issues?page=5", Rel: "last"},
+			},
+		},
+		{
+			name:  "params",
+			value: `</a,b>; REL=next; anchor="#foo"; title="a \"quoted\", title"; type=text/html; rel=ignored`,
+			expected: []LinkValue{
+				{URL: "/a,b", Rel: "next", Anchor: "#foo", Params: map[string]string{"title": `a "quoted", title`, "type": "text/html"}},
+			},
+		},
+		{
+			name:  "invalid-skipped",
+			value: `invalid; rel="x,y", </b>; rel="prev first"`,
+			expected: []LinkValue{
+				{URL: "/b", Rel: "prev first"},
+			},
+		},
+		{
+			name:     "empty",
+			value:    ``,
+			expected: nil,
+		},
+	}
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			Equal(t, ParseLink(tt.value), tt.expected)
+		})
+	}
+}
+
+func TestFormatLinks(t *testing.T) {
+	links := []LinkValue{
+		{URL: "/page/2", Rel: "next"},
+		{URL: "/page/1", Rel: "prev first", Anchor: "#top", Params: map[string]string{"title*": "UTF-8'en'%E2%82%AC", "title": `say "hi"`}},
+	}
+	value := FormatLinks(links...)
+	Equal(t, value, `</page/2>; rel="next", </page/1>; rel="prev first"; anchor="#top"; title="say \"hi\""; title*=UTF-8'en'%E2%82%AC`)
+
+	headers := make(http.Header)
+	headers.Add(Link, links[0].String())
+	headers.Add(Link, links[1].String())
+	parsed := ParseLinks(headers)
+	Equal(t, parsed, links)
+	Equal(t, parsed[1].HasRel("first"), true)
+	Equal(t, parsed[1].HasRel("next"), false)
+}
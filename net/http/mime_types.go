@@ -12,6 +12,8 @@ const (
 	ApplicationXMLNoCharset  string = "application/xml"
 	ApplicationXML           string = ApplicationXMLNoCharset + charsetUTF8
 	ApplicationForm          string = "application/x-www-form-urlencoded"
+	ApplicationNDJSON        string = "application/x-ndjson"
+	ApplicationProblemJSON   string = "application/problem+json"
 	ApplicationProtobuf      string = "application/protobuf"
 	ApplicationMsgpack       string = "application/msgpack"
 	ApplicationWasm          string = "application/wasm"
@@ -26,6 +28,7 @@ const (
 	TextCSSNoCharset         string = "text/css"
 	TextCSS                  string = TextCSSNoCharset + charsetUTF8
 	TextCSV                  string = "text/csv"
+	TextEventStream          string = "text/event-stream"
 	ImagePNG                 string = "image/png"
 	ImageGIF                 string = "image/gif"
 	ImageSVG                 string = "image/svg+xml"
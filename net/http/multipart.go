@@ -0,0 +1,211 @@
+package httpext
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"net/textproto"
+	"net/url"
+	"strings"
+
+	bytesext "github.com/go-playground/pkg/v5/bytes"
+	ioext "github.com/go-playground/pkg/v5/io"
+)
+
+const sniffLen = 512
+
+// ErrDisallowedContentType is returned when the sniffed content type of an uploaded file is not allowed.
+type ErrDisallowedContentType struct {
+	// FieldName is the form field name of the file part.
+	FieldName string
+
+	// FileName is the client supplied file name of the file part.
+	FileName string
+
+	// ContentType is the sniffed content type of the file part.
+	ContentType string
+}
+
+// Error returns the error message for the disallowed content type.
+func (e ErrDisallowedContentType) Error() string {
+	return "disallowed content type " + e.ContentType + " for file " + e.FileName
+}
+
+// MultipartFilePart represents a streamed file part of a multipart form.
+type MultipartFilePart struct {
+	// FieldName is the form field name of the file part.
+	FieldName string
+
+	// FileName is the client supplied file name, with any directory components removed.
+	FileName string
+
+	// ContentType is the sniffed content type of the file, not the client supplied one.
+	ContentType string
+
+	// Header is the raw MIME header of the part.
+	Header textproto.MIMEHeader
+
+	// Reader streams the file contents and is limited to the configured maximum part size.
+	Reader io.Reader
+}
+
+// MultipartFileFn is called for each file part of a multipart form, in the order received, and is responsible for
+// streaming the file to its final destination.
+//
+// Any unread data is discarded, while still being subject to the limits, after the function returns.
+type MultipartFileFn func(file MultipartFilePart) error
+
+// MultipartDecoder is used to decode a multipart form by streaming it, rather than buffering it in memory and
+// temporary files, while enforcing limits.
+//
+// The `MultipartDecoder` is designed to be stateless and reusable. Configuration is also copy and so a base
+// `MultipartDecoder` can be used and changed for one-off requests.
+type MultipartDecoder struct {
+	maxPartBytes  bytesext.Bytes
+	maxTotalBytes bytesext.Bytes
+	allowedTypes  []string
+}
+
+// NewMultipartDecoder returns a new `MultipartDecoder` with sane default values.
+//
+// The default values are:
+//   - `MaxPartBytes` is 10MiB.
+//   - `MaxTotalBytes` is 32MiB.
+//   - `AllowedTypes` is empty, allowing any content type.
+func NewMultipartDecoder() MultipartDecoder {
+	return MultipartDecoder{
+		maxPartBytes:  10 * bytesext.MiB,
+		maxTotalBytes: 32 * bytesext.MiB,
+	}
+}
+
+// MaxPartBytes sets the maximum size of any single part, file or text field.
+func (d MultipartDecoder) MaxPartBytes(i bytesext.Bytes) MultipartDecoder {
+	d.maxPartBytes = i
+	return d
+}
+
+// MaxTotalBytes sets the maximum size of the entire request body.
+func (d MultipartDecoder) MaxTotalBytes(i bytesext.Bytes) MultipartDecoder {
+	d.maxTotalBytes = i
+	return d
+}
+
+// AllowedTypes sets the content types allowed for file parts, which are checked against the sniffed content type
+// of the file. A type may end with a wildcard eg. "image/*".
+//
+// No types, the default, allows any content type.
+func (d MultipartDecoder) AllowedTypes(types ...string) MultipartDecoder {
+	d.allowedTypes = types
+	return d
+}
+
+// Decode streams the requests multipart form, passing each file part to the provided function and decoding the
+// remaining text fields into the provided struct using the DefaultFormDecoder.
+//
+// If fn is nil file parts are discarded. Exceeding either limit returns an ioext.ErrLimitedReaderEOF error and a file
+// with a disallowed content type returns ErrDisallowedContentType.
+//
+// NOTE: when QueryParamsOption=QueryParams the query params will be parsed and included eg. route /user?test=true 'test'
+// is added to the parsed form values.
+func (d MultipartDecoder) Decode(r *http.Request, qp QueryParamsOption, v interface{}, fn MultipartFileFn) error {
+	_, params, err := mime.ParseMediaType(r.Header.Get(ContentType))
+	if err != nil {
+		return err
+	}
+	boundary := params["boundary"]
+	if boundary == "" {
+		return http.ErrMissingBoundary
+	}
+	mr := multipart.NewReader(ioext.LimitReader(r.Body, d.maxTotalBytes), boundary)
+
+	values := make(url.Values)
+	for {
+		part, err := mr.NextPart()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		if err = d.decodePart(part, values, fn); err != nil {
+			_ = part.Close()
+			return err
+		}
+		if err = part.Close(); err != nil {
+			return err
+		}
+	}
+
+	if qp == QueryParams {
+		for k, vals := range r.URL.Query() {
+			values[k] = append(values[k], vals...)
+		}
+	}
+	return DefaultFormDecoder.Decode(v, values)
+}
+
+func (d MultipartDecoder) decodePart(part *multipart.Part, values url.Values, fn MultipartFileFn) error {
+	name := part.FormName()
+	if name == "" {
+		return nil
+	}
+	lr := ioext.LimitReader(part, d.maxPartBytes)
+
+	filename := part.FileName()
+	if filename == "" {
+		b, err := io.ReadAll(lr)
+		if err != nil {
+			return err
+		}
+		values.Add(name, string(b))
+		return nil
+	}
+
+	sniff := make([]byte, sniffLen)
+	n, err := io.ReadFull(lr, sniff)
+	if err != nil && err != io.EOF && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return err
+	}
+	sniff = sniff[:n]
+
+	contentType := http.DetectContentType(sniff)
+	if !d.isAllowedType(contentType) {
+		return ErrDisallowedContentType{FieldName: name, FileName: filename, ContentType: contentType}
+	}
+
+	if fn != nil {
+		if err = fn(MultipartFilePart{
+			FieldName:   name,
+			FileName:    filename,
+			ContentType: contentType,
+			Header:      part.Header,
+			Reader:      io.MultiReader(bytes.NewReader(sniff), lr),
+		}); err != nil {
+			return err
+		}
+	}
+	_, err = io.Copy(io.Discard, lr)
+	return err
+}
+
+func (d MultipartDecoder) isAllowedType(contentType string) bool {
+	if len(d.allowedTypes) == 0 {
+		return true
+	}
+	if idx := strings.IndexByte(contentType, ';'); idx != -1 {
+		contentType = contentType[:idx]
+	}
+	for _, allowed := range d.allowedTypes {
+		if strings.HasSuffix(allowed, "/*") {
+			if strings.HasPrefix(contentType, allowed[:len(allowed)-1]) {
+				return true
+			}
+		} else if strings.EqualFold(allowed, contentType) {
+			return true
+		}
+	}
+	return false
+}
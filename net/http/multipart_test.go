@@ -0,0 +1,87 @@
+package httpext
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/go-playground/assert/v2"
+	ioext "github.com/go-playground/pkg/v5/io"
+)
+
+func newMultipartRequest(t *testing.T, url string, fields map[string]string, files map[string][]byte) *http.Request {
+	body := &bytes.Buffer{}
+	writer := multipart.NewWriter(body)
+	for k, v := range fields {
+		Equal(t, writer.WriteField(k, v), nil)
+	}
+	for name, b := range files {
+		w, err := writer.CreateFormFile(name, "../"+name+".bin")
+		Equal(t, err, nil)
+		_, err = w.Write(b)
+		Equal(t, err, nil)
+	}
+	Equal(t, writer.Close(), nil)
+
+	r := httptest.NewRequest(http.MethodPost, url, body)
+	r.Header.Set(ContentType, writer.FormDataContentType())
+	return r
+}
+
+func TestMultipartDecoder(t *testing.T) {
+	type TestStruct struct {
+		ID   int    `form:"id"`
+		Name string `form:"name"`
+	}
+	png := append([]byte("\x89PNG\x0D\x0A\x1A\x0A"), bytes.Repeat([]byte{0}, 1024)...)
+
+	r := newMultipartRequest(t, "/?id=3", map[string]string{"name": "joeybloggs"}, map[string][]byte{"avatar": png})
+
+	var (
+		test  TestStruct
+		files []MultipartFilePart
+		data  []byte
+	)
+	err := NewMultipartDecoder().AllowedTypes("image/*").Decode(r, QueryParams, &test, func(file MultipartFilePart) (err error) {
+		files = append(files, file)
+		data, err = io.ReadAll(file.Reader)
+		return
+	})
+	Equal(t, err, nil)
+	Equal(t, test, TestStruct{ID: 3, Name: "joeybloggs"})
+	Equal(t, len(files), 1)
+	Equal(t, files[0].FieldName, "avatar")
+	Equal(t, files[0].FileName, "avatar.bin")
+	Equal(t, files[0].ContentType, ImagePNG)
+	Equal(t, data, png)
+}
+
+func TestMultipartDecoderLimits(t *testing.T) {
+	var test struct {
+		Name string `form:"name"`
+	}
+
+	r := newMultipartRequest(t, "/", map[string]string{"name": "joeybloggs"}, map[string][]byte{"file": bytes.Repeat([]byte("a"), 2048)})
+	err := NewMultipartDecoder().MaxPartBytes(1024).Decode(r, NoQueryParams, &test, nil)
+	Equal(t, errors.Is(err, ioext.ErrLimitedReaderEOF), true)
+
+	r = newMultipartRequest(t, "/", map[string]string{"name": "joeybloggs"}, map[string][]byte{"file": bytes.Repeat([]byte("a"), 2048)})
+	err = NewMultipartDecoder().MaxTotalBytes(1024).Decode(r, NoQueryParams, &test, nil)
+	Equal(t, errors.Is(err, ioext.ErrLimitedReaderEOF), true)
+
+	r = newMultipartRequest(t, "/", map[string]string{"name": "joeybloggs"}, map[string][]byte{"file": []byte("plain text")})
+	err = NewMultipartDecoder().AllowedTypes(ImagePNG, ApplicationPDF).Decode(r, NoQueryParams, &test, nil)
+	var dct ErrDisallowedContentType
+	Equal(t, errors.As(err, &dct), true)
+	Equal(t, dct.FieldName, "file")
+	Equal(t, dct.ContentType, TextPlain)
+
+	r = newMultipartRequest(t, "/", map[string]string{"name": "joeybloggs"}, nil)
+	r.Header.Set(ContentType, MultipartForm)
+	err = NewMultipartDecoder().Decode(r, NoQueryParams, &test, nil)
+	Equal(t, err, http.ErrMissingBoundary)
+}
@@ -0,0 +1,213 @@
+//go:build go1.18
+// +build go1.18
+
+package httpext
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	bytesext "github.com/go-playground/pkg/v5/bytes"
+	ioext "github.com/go-playground/pkg/v5/io"
+	timeext "github.com/go-playground/pkg/v5/time"
+	. "github.com/go-playground/pkg/v5/values/option"
+	. "github.com/go-playground/pkg/v5/values/result"
+)
+
+// NDJSONEncoder encodes values as newline-delimited JSON to an http.ResponseWriter, periodically flushing the
+// written lines to the client.
+//
+// The NDJSONEncoder is not safe for concurrent use.
+type NDJSONEncoder struct {
+	enc           *json.Encoder
+	flusher       http.Flusher
+	flushInterval time.Duration
+	lastFlush     timeext.Instant
+}
+
+// NewNDJSONEncoder sets the Content-Type header, writes the status code and returns a new NDJSONEncoder which will
+// flush written lines when at least flushInterval has elapsed since the last flush.
+//
+// A flushInterval of 0 will flush after every encoded value.
+func NewNDJSONEncoder(w http.ResponseWriter, status int, flushInterval time.Duration) *NDJSONEncoder {
+	w.Header().Set(ContentType, ApplicationNDJSON)
+	w.WriteHeader(status)
+	flusher, _ := w.(http.Flusher)
+	return &NDJSONEncoder{
+		enc:           json.NewEncoder(w),
+		flusher:       flusher,
+		flushInterval: flushInterval,
+		lastFlush:     timeext.NewInstant(),
+	}
+}
+
+// Encode writes the JSON encoding of v followed by a newline, flushing if the flush interval has elapsed.
+func (e *NDJSONEncoder) Encode(v any) error {
+	if err := e.enc.Encode(v); err != nil {
+		return err
+	}
+	if e.lastFlush.Elapsed() >= e.flushInterval {
+		e.Flush()
+	}
+	return nil
+}
+
+// Flush flushes any buffered lines to the client.
+func (e *NDJSONEncoder) Flush() {
+	if e.flusher != nil {
+		e.flusher.Flush()
+	}
+	e.lastFlush = timeext.NewInstant()
+}
+
+// NDJSONFromChannel streams the values received from the provided channel as newline-delimited JSON until the
+// channel is closed.
+//
+// Buffered lines are flushed every flushInterval and whenever the channel has no value immediately available.
+func NDJSONFromChannel[T any](w http.ResponseWriter, status int, flushInterval time.Duration, values <-chan T) error {
+	enc := NewNDJSONEncoder(w, status, flushInterval)
+	defer enc.Flush()
+
+	for {
+		var (
+			v  T
+			ok bool
+		)
+		select {
+		case v, ok = <-values:
+		default:
+			enc.Flush()
+			v, ok = <-values
+		}
+		if !ok {
+			return nil
+		}
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+	}
+}
+
+// NDJSONFromIterator streams the values returned by the provided iterator function as newline-delimited JSON until
+// it returns None.
+//
+// Buffered lines are flushed every flushInterval.
+func NDJSONFromIterator[T any](w http.ResponseWriter, status int, flushInterval time.Duration, next func() Option[T]) error {
+	enc := NewNDJSONEncoder(w, status, flushInterval)
+	defer enc.Flush()
+
+	for {
+		v := next()
+		if v.IsNone() {
+			return nil
+		}
+		if err := enc.Encode(v.Unwrap()); err != nil {
+			return err
+		}
+	}
+}
+
+// NDJSONDecoder decodes newline-delimited JSON one line at a time into the type T.
+type NDJSONDecoder[T any] struct {
+	line    ndjsonLineReader
+	maxLine bytesext.Bytes
+	done    bool
+}
+
+// NewNDJSONDecoder returns a new NDJSONDecoder reading from r and limiting each line to maxLineBytes via an
+// ioext.LimitReader.
+func NewNDJSONDecoder[T any](r io.Reader, maxLineBytes bytesext.Bytes) *NDJSONDecoder[T] {
+	return &NDJSONDecoder[T]{
+		line:    ndjsonLineReader{br: bufio.NewReader(r)},
+		maxLine: maxLineBytes,
+	}
+}
+
+// DecodeNDJSON returns a new NDJSONDecoder for the request body, limiting each line to maxLineBytes.
+//
+// The Content-Type e.g. "application/x-ndjson" and http method are not checked.
+func DecodeNDJSON[T any](r *http.Request, maxLineBytes bytesext.Bytes) (*NDJSONDecoder[T], error) {
+	return newNDJSONDecoder[T](r.Header, r.Body, maxLineBytes)
+}
+
+// DecodeResponseNDJSON returns a new NDJSONDecoder for the response body, limiting each line to maxLineBytes.
+//
+// NOTE: it is up to the caller to close the response body.
+func DecodeResponseNDJSON[T any](r *http.Response, maxLineBytes bytesext.Bytes) (*NDJSONDecoder[T], error) {
+	return newNDJSONDecoder[T](r.Header, r.Body, maxLineBytes)
+}
+
+func newNDJSONDecoder[T any](headers http.Header, body io.Reader, maxLineBytes bytesext.Bytes) (*NDJSONDecoder[T], error) {
+	if encoding := headers.Get(ContentEncoding); encoding == Gzip {
+		gzr, err := gzip.NewReader(body)
+		if err != nil {
+			return nil, err
+		}
+		body = gzr
+	}
+	return NewNDJSONDecoder[T](body, maxLineBytes), nil
+}
+
+// Next decodes and returns the next line, or None once the end of the stream has been reached.
+//
+// A line that fails to decode returns its error and decoding may continue with the next line, however reading
+// errors, including exceeding the line limit, are terminal and None is returned for all subsequent calls. Empty
+// lines are skipped.
+func (d *NDJSONDecoder[T]) Next() Option[Result[T, error]] {
+	for !d.done {
+		d.line.eol = false
+		b, err := io.ReadAll(ioext.LimitReader(&d.line, d.maxLine))
+		if err != nil {
+			d.done = true
+			return Some(Err[T, error](err))
+		}
+		if !d.line.eol {
+			d.done = true
+		}
+		b = bytes.TrimSpace(b)
+		if len(b) == 0 {
+			continue
+		}
+		var v T
+		if err = json.Unmarshal(b, &v); err != nil {
+			return Some(Err[T, error](err))
+		}
+		return Some(Ok[T, error](v))
+	}
+	return None[Result[T, error]]()
+}
+
+// ndjsonLineReader reads from the underlying bufio.Reader until the end of the current line, returning io.EOF at
+// the newline.
+type ndjsonLineReader struct {
+	br  *bufio.Reader
+	eol bool
+}
+
+func (l *ndjsonLineReader) Read(p []byte) (n int, err error) {
+	if l.eol {
+		return 0, io.EOF
+	}
+	if l.br.Buffered() == 0 {
+		if _, err = l.br.Peek(1); err != nil {
+			return 0, err
+		}
+	}
+	b, _ := l.br.Peek(l.br.Buffered())
+	if idx := bytes.IndexByte(b, '\n'); idx != -1 {
+		if idx <= len(p) {
+			n = copy(p, b[:idx])
+			_, _ = l.br.Discard(idx + 1)
+			l.eol = true
+			return n, nil
+		}
+	}
+	n = copy(p, b)
+	_, _ = l.br.Discard(n)
+	return n, nil
+}
@@ -0,0 +1,114 @@
+//go:build go1.18
+// +build go1.18
+
+package httpext
+
+import (
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/go-playground/assert/v2"
+	ioext "github.com/go-playground/pkg/v5/io"
+	. "github.com/go-playground/pkg/v5/values/option"
+)
+
+type ndjsonTest struct {
+	ID int `json:"id"`
+}
+
+func TestNDJSONFromChannel(t *testing.T) {
+	values := make(chan ndjsonTest, 3)
+	values <- ndjsonTest{ID: 1}
+	values <- ndjsonTest{ID: 2}
+	values <- ndjsonTest{ID: 3}
+	close(values)
+
+	w := httptest.NewRecorder()
+	err := NDJSONFromChannel(w, http.StatusOK, 0, values)
+	Equal(t, err, nil)
+	Equal(t, w.Code, http.StatusOK)
+	Equal(t, w.Header().Get(ContentType), ApplicationNDJSON)
+	Equal(t, w.Body.String(), "{\"id\":1}\n{\"id\":2}\n{\"id\":3}\n")
+	Equal(t, w.Flushed, true)
+}
+
+func TestNDJSONFromIterator(t *testing.T) {
+	var i int
+	w := httptest.NewRecorder()
+	err := NDJSONFromIterator(w, http.StatusOK, 0, func() Option[ndjsonTest] {
+		if i == 2 {
+			return None[ndjsonTest]()
+		}
+		i++
+		return Some(ndjsonTest{ID: i})
+	})
+	Equal(t, err, nil)
+	Equal(t, w.Body.String(), "{\"id\":1}\n{\"id\":2}\n")
+
+	err = NDJSONFromIterator(httptest.NewRecorder(), http.StatusOK, 0, func() Option[func()] {
+		return Some(func() {})
+	})
+	NotEqual(t, err, nil)
+}
+
+func TestNDJSONDecoder(t *testing.T) {
+	body := "{\"id\":1}\r\n\n{\"id\":\"bad\"}\n{\"id\":3}"
+	r := httptest.NewRequest(http.MethodPost, "/", strings.NewReader(body))
+	dec, err := DecodeNDJSON[ndjsonTest](r, 16)
+	Equal(t, err, nil)
+
+	v := dec.Next()
+	Equal(t, v.IsSome(), true)
+	Equal(t, v.Unwrap().Unwrap(), ndjsonTest{ID: 1})
+
+	v = dec.Next()
+	Equal(t, v.IsSome(), true)
+	Equal(t, v.Unwrap().IsErr(), true)
+
+	v = dec.Next()
+	Equal(t, v.IsSome(), true)
+	Equal(t, v.Unwrap().Unwrap(), ndjsonTest{ID: 3})
+
+	Equal(t, dec.Next().IsNone(), true)
+	Equal(t, dec.Next().IsNone(), true)
+}
+
+func TestNDJSONDecoderLineLimit(t *testing.T) {
+	body := "{\"id\":1}\n{\"id\":100000000000}\n{\"id\":3}\n"
+	dec := NewNDJSONDecoder[ndjsonTest](strings.NewReader(body), 10)
+
+	v := dec.Next()
+	Equal(t, v.Unwrap().Unwrap(), ndjsonTest{ID: 1})
+
+	v = dec.Next()
+	Equal(t, v.IsSome(), true)
+	Equal(t, errors.Is(v.Unwrap().Err(), ioext.ErrLimitedReaderEOF), true)
+	Equal(t, dec.Next().IsNone(), true)
+}
+
+func TestDecodeResponseNDJSONGzip(t *testing.T) {
+	var buff bytes.Buffer
+	gzw := gzip.NewWriter(&buff)
+	_, err := gzw.Write([]byte("{\"id\":1}\n{\"id\":2}\n"))
+	Equal(t, err, nil)
+	Equal(t, gzw.Close(), nil)
+
+	w := httptest.NewRecorder()
+	w.Header().Set(ContentEncoding, Gzip)
+	_, _ = w.Write(buff.Bytes())
+
+	dec, err := DecodeResponseNDJSON[ndjsonTest](w.Result(), 1024)
+	Equal(t, err, nil)
+
+	var ids []int
+	for v := dec.Next(); v.IsSome(); v = dec.Next() {
+		Equal(t, v.Unwrap().IsOk(), true)
+		ids = append(ids, v.Unwrap().Unwrap().ID)
+	}
+	Equal(t, ids, []int{1, 2})
+}
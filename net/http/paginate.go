@@ -0,0 +1,154 @@
+//go:build go1.18
+// +build go1.18
+
+package httpext
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+
+	ioext "github.com/go-playground/pkg/v5/io"
+	. "github.com/go-playground/pkg/v5/values/option"
+	. "github.com/go-playground/pkg/v5/values/result"
+)
+
+// PageRequestFn is a function used to build the request for the page at the provided URL.
+type PageRequestFn func(ctx context.Context, pageURL string) Result[*http.Request, error]
+
+// NextPageFn is a function used to determine the URL of the next page from the current pages URL, response and
+// decoded items, returning None when there are no more pages.
+//
+// NOTE: the response body has already been consumed and closed.
+type NextPageFn[T any] func(current *url.URL, resp *http.Response, items []T) Option[string]
+
+// NextLinkRel returns a `NextPageFn` which follows the `rel="next"` link of the responses Link header, resolved
+// relative to the current pages URL.
+func NextLinkRel[T any]() NextPageFn[T] {
+	return func(current *url.URL, resp *http.Response, _ []T) Option[string] {
+		for _, link := range ParseLinks(resp.Header) {
+			if !link.HasRel("next") {
+				continue
+			}
+			u, err := current.Parse(link.URL)
+			if err != nil {
+				return None[string]()
+			}
+			return Some(u.String())
+		}
+		return None[string]()
+	}
+}
+
+// Paginator lazily fetches, using the `Retryer`, and decodes pages of items yielding one item at a time.
+//
+// The Paginator is not safe for concurrent use.
+type Paginator[T any] struct {
+	retryer  Retryer
+	url      string
+	reqFn    PageRequestFn
+	nextFn   NextPageFn[T]
+	expected []int
+	items    []T
+	done     bool
+}
+
+// Paginate returns a new `Paginator` starting at the provided startURL, decoding each page into `[]T` using the
+// `Retryer`s `DecodeFn` and determining the next page using nextFn.
+//
+// If reqFn is nil a GET request without a body is used and if nextFn is nil `NextLinkRel` is used, which covers
+// GitHub-style REST pagination. Each page is fetched with the full retry semantics of the `Retryer`.
+func Paginate[T any](r Retryer, startURL string, reqFn PageRequestFn, nextFn NextPageFn[T], expectedResponseCodes ...int) *Paginator[T] {
+	if reqFn == nil {
+		reqFn = func(ctx context.Context, pageURL string) Result[*http.Request, error] {
+			return NewRequestBuilder(http.MethodGet, pageURL).NoBody()(ctx)
+		}
+	}
+	if nextFn == nil {
+		nextFn = NextLinkRel[T]()
+	}
+	return &Paginator[T]{
+		retryer:  r,
+		url:      startURL,
+		reqFn:    reqFn,
+		nextFn:   nextFn,
+		expected: expectedResponseCodes,
+	}
+}
+
+// Next returns the next item, fetching the next page when required, or None once all pages have been consumed.
+//
+// An error fetching or decoding a page, after all retries, is returned once after which None is returned for all
+// subsequent calls.
+func (p *Paginator[T]) Next(ctx context.Context) Option[Result[T, error]] {
+	for len(p.items) == 0 {
+		if p.done {
+			return None[Result[T, error]]()
+		}
+		if err := p.fetch(ctx); err != nil {
+			p.done = true
+			return Some(Err[T, error](err))
+		}
+	}
+	item := p.items[0]
+	p.items = p.items[1:]
+	return Some(Ok[T, error](item))
+}
+
+type page[T any] struct {
+	resp  *http.Response
+	items []T
+}
+
+func (p *Paginator[T]) fetch(ctx context.Context) error {
+	r := p.retryer
+	current, err := url.Parse(p.url)
+	if err != nil {
+		return err
+	}
+	st := r.newSendState()
+
+	result := retryer[page[T]](r).
+		Do(ctx, func(ctx context.Context) Result[page[T], error] {
+			resp, err := r.send(ctx, func(ctx context.Context) Result[*http.Request, error] {
+				return p.reqFn(ctx, p.url)
+			}, st, r.client.Do)
+			if err != nil {
+				return Err[page[T], error](err)
+			}
+			defer func() {
+				_, _ = io.Copy(io.Discard, ioext.LimitReader(resp.Body, r.maxBytes))
+				_ = resp.Body.Close()
+			}()
+
+			if len(p.expected) > 0 {
+				for _, code := range p.expected {
+					if resp.StatusCode == code {
+						goto DECODE
+					}
+				}
+				return Err[page[T], error](r.statusCodeErr(ctx, resp))
+			}
+
+		DECODE:
+			var items []T
+			if err = r.decodeFn(ctx, resp, r.maxBytes, &items); err != nil {
+				return Err[page[T], error](err)
+			}
+			return Ok[page[T], error](page[T]{resp: resp, items: items})
+		})
+	if result.IsErr() {
+		return result.Err()
+	}
+
+	pg := result.Unwrap()
+	p.items = pg.items
+	// guard against a next page pointing to itself which would otherwise never end
+	if next := p.nextFn(current, pg.resp, pg.items); next.IsSome() && next.Unwrap() != p.url {
+		p.url = next.Unwrap()
+	} else {
+		p.done = true
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+//go:build go1.18
+// +build go1.18
+
+package httpext
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"testing"
+
+	. "github.com/go-playground/assert/v2"
+	errorsext "github.com/go-playground/pkg/v5/errors"
+	. "github.com/go-playground/pkg/v5/values/option"
+)
+
+func TestPaginate(t *testing.T) {
+	var failed bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		// fail the second page once to ensure pages are retried
+		if page == 2 && !failed {
+			failed = true
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		if page < 3 {
+			w.Header().Set(Link, FormatLinks(LinkValue{URL: "?page=" + strconv.Itoa(page+1), Rel: "next"}))
+		}
+		_ = JSON(w, http.StatusOK, []int{page*10 + 1, page*10 + 2})
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	p := Paginate[int](NewRetryer().Backoff(nil), server.URL, nil, nil, http.StatusOK)
+
+	var items []int
+	for item := p.Next(ctx); item.IsSome(); item = p.Next(ctx) {
+		Equal(t, item.Unwrap().IsOk(), true)
+		items = append(items, item.Unwrap().Unwrap())
+	}
+	Equal(t, items, []int{11, 12, 21, 22, 31, 32})
+	Equal(t, failed, true)
+}
+
+func TestPaginateCursor(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		after, _ := strconv.Atoi(r.URL.Query().Get("after"))
+		if after >= 4 {
+			_ = JSON(w, http.StatusOK, []int{})
+			return
+		}
+		_ = JSON(w, http.StatusOK, []int{after + 1, after + 2})
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	p := Paginate[int](NewRetryer(), server.URL, nil, func(current *url.URL, _ *http.Response, items []int) Option[string] {
+		if len(items) == 0 {
+			return None[string]()
+		}
+		q := current.Query()
+		q.Set("after", strconv.Itoa(items[len(items)-1]))
+		current.RawQuery = q.Encode()
+		return Some(current.String())
+	}, http.StatusOK)
+
+	var items []int
+	for item := p.Next(ctx); item.IsSome(); item = p.Next(ctx) {
+		items = append(items, item.Unwrap().Unwrap())
+	}
+	Equal(t, items, []int{1, 2, 3, 4})
+}
+
+func TestPaginateError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	p := Paginate[int](NewRetryer().MaxAttempts(errorsext.MaxAttempts, 1), server.URL, nil, nil, http.StatusOK)
+
+	item := p.Next(ctx)
+	Equal(t, item.IsSome(), true)
+	Equal(t, item.Unwrap().IsErr(), true)
+	Equal(t, p.Next(ctx).IsNone(), true)
+}
@@ -0,0 +1,43 @@
+package httpext
+
+import (
+	"encoding/json"
+	"net/http"
+)
+
+// ProblemDetails represents an RFC 9457 problem details object.
+type ProblemDetails struct {
+	// Type is a URI reference identifying the problem type, "about:blank" when omitted.
+	Type string `json:"type,omitempty"`
+
+	// Title is a short human-readable summary of the problem type.
+	Title string `json:"title,omitempty"`
+
+	// Status is the HTTP status code of the response.
+	Status int `json:"status,omitempty"`
+
+	// Detail is a human-readable explanation specific to this occurrence of the problem.
+	Detail string `json:"detail,omitempty"`
+
+	// Instance is a URI reference identifying the specific occurrence of the problem.
+	Instance string `json:"instance,omitempty"`
+}
+
+// ProblemJSON marshals the provided problem details and returns it as application/problem+json with its status
+// code, defaulting to 500 if not set.
+func ProblemJSON(w http.ResponseWriter, p ProblemDetails) error {
+	if p.Status == 0 {
+		p.Status = http.StatusInternalServerError
+	}
+	if p.Title == "" {
+		p.Title = http.StatusText(p.Status)
+	}
+	b, err := json.Marshal(p)
+	if err != nil {
+		return err
+	}
+	w.Header().Set(ContentType, ApplicationProblemJSON)
+	w.WriteHeader(p.Status)
+	_, err = w.Write(b)
+	return err
+}
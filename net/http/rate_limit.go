@@ -0,0 +1,175 @@
+//go:build go1.18
+// +build go1.18
+
+package httpext
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	. "github.com/go-playground/pkg/v5/values/option"
+)
+
+// epochThreshold is used to distinguish a rate limit reset sent as a unix epoch timestamp, as some APIs such as
+// GitHub do, from the standard delta seconds.
+const epochThreshold = 1_000_000_000
+
+// rateLimitPrefixes are the header prefixes checked, in order, for rate limit information.
+var rateLimitPrefixes = []string{"RateLimit-", "X-RateLimit-", "X-Rate-Limit-"}
+
+// RateLimit represents the rate limit information returned by a server.
+type RateLimit struct {
+	// Limit is the optional request quota in the current window.
+	Limit Option[int64]
+
+	// Remaining is the remaining request quota in the current window.
+	Remaining int64
+
+	// Reset is the optional time until the quota resets.
+	Reset Option[time.Duration]
+}
+
+// HasRateLimit parses the standard `RateLimit-Limit`, `RateLimit-Remaining` and `RateLimit-Reset` headers, or the
+// common `X-RateLimit-*` and `X-Rate-Limit-*` variants, returning the rate limit if a remaining quota is present.
+//
+// A reset value that looks like a unix epoch timestamp, rather than delta seconds, is converted to a duration.
+func HasRateLimit(headers http.Header) Option[RateLimit] {
+	for _, prefix := range rateLimitPrefixes {
+		remaining := parseRateLimitInt(headers.Get(prefix + "Remaining"))
+		if remaining.IsNone() {
+			continue
+		}
+		rl := RateLimit{
+			Limit:     parseRateLimitInt(headers.Get(prefix + "Limit")),
+			Remaining: remaining.Unwrap(),
+		}
+		if reset := parseRateLimitInt(headers.Get(prefix + "Reset")); reset.IsSome() {
+			n := reset.Unwrap()
+			if n >= epochThreshold {
+				d := time.Until(time.Unix(n, 0))
+				if d < 0 {
+					d = 0
+				}
+				rl.Reset = Some(d)
+			} else {
+				rl.Reset = Some(time.Duration(n) * time.Second)
+			}
+		}
+		return Some(rl)
+	}
+	return None[RateLimit]()
+}
+
+// parseRateLimitInt parses the leading integer of a rate limit header value, ignoring any quota policy eg.
+// "100, 100;w=60".
+func parseRateLimitInt(s string) Option[int64] {
+	if idx := strings.IndexAny(s, ",;"); idx != -1 {
+		s = s[:idx]
+	}
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return None[int64]()
+	}
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || n < 0 {
+		return None[int64]()
+	}
+	return Some(n)
+}
+
+// rateLimitWait returns the time to wait until the rate limit resets if the error is an `ErrStatusCode` whose
+// response indicates the quota has been exhausted.
+func rateLimitWait(err error) Option[time.Duration] {
+	var sce ErrStatusCode
+	if !errors.As(err, &sce) || sce.Headers == nil {
+		return None[time.Duration]()
+	}
+	if rl := HasRateLimit(sce.Headers); rl.IsSome() && rl.Unwrap().Remaining == 0 && rl.Unwrap().Reset.IsSome() {
+		return rl.Unwrap().Reset
+	}
+	return None[time.Duration]()
+}
+
+// RateLimitThrottle is used to pre-emptively delay requests, per host, when the rate limit quota returned by the
+// server is almost exhausted rather than waiting to be rejected.
+//
+// A RateLimitThrottle is safe for concurrent use and should be shared between `Retryer`s calling the same hosts.
+type RateLimitThrottle struct {
+	m         sync.Mutex
+	threshold int64
+	hosts     map[string]rateLimitState
+}
+
+type rateLimitState struct {
+	remaining int64
+	resetAt   time.Time
+}
+
+// NewRateLimitThrottle returns a new `RateLimitThrottle` which starts delaying requests to a host once its remaining
+// quota is at or below threshold, spreading the remaining requests evenly until the quota resets and waiting for
+// the reset once exhausted.
+func NewRateLimitThrottle(threshold int64) *RateLimitThrottle {
+	return &RateLimitThrottle{
+		threshold: threshold,
+		hosts:     make(map[string]rateLimitState),
+	}
+}
+
+// Wait blocks until the next request to the host may be made or the context is cancelled, returning the context
+// error if cancelled.
+func (t *RateLimitThrottle) Wait(ctx context.Context, host string) error {
+	wait := t.reserve(host, time.Now())
+	if wait <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve returns how long to wait before the next request to the host, accounting for the request about to be
+// made.
+func (t *RateLimitThrottle) reserve(host string, now time.Time) time.Duration {
+	t.m.Lock()
+	defer t.m.Unlock()
+
+	state, ok := t.hosts[host]
+	if !ok || !now.Before(state.resetAt) {
+		delete(t.hosts, host)
+		return 0
+	}
+	untilReset := state.resetAt.Sub(now)
+	if state.remaining <= 0 {
+		return untilReset
+	}
+	state.remaining--
+	t.hosts[host] = state
+	if state.remaining+1 > t.threshold {
+		return 0
+	}
+	return untilReset / time.Duration(state.remaining+2)
+}
+
+// Observe records the rate limit information, if any, from the response headers for the host.
+func (t *RateLimitThrottle) Observe(host string, headers http.Header) {
+	rl := HasRateLimit(headers)
+	if rl.IsNone() || rl.Unwrap().Reset.IsNone() {
+		return
+	}
+	t.m.Lock()
+	defer t.m.Unlock()
+	t.hosts[host] = rateLimitState{
+		remaining: rl.Unwrap().Remaining,
+		resetAt:   time.Now().Add(rl.Unwrap().Reset.Unwrap()),
+	}
+}
@@ -0,0 +1,181 @@
+//go:build go1.18
+// +build go1.18
+
+package httpext
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/go-playground/assert/v2"
+	. "github.com/go-playground/pkg/v5/values/option"
+	. "github.com/go-playground/pkg/v5/values/result"
+)
+
+func TestHasRateLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		headers  map[string]string
+		expected Option[RateLimit]
+	}{
+		{
+			name:     "none",
+			headers:  map[string]string{},
+			expected: None[RateLimit](),
+		},
+		{
+			name:    "standard",
+			headers: map[string]string{"RateLimit-Limit": "100, 100;w=60", "RateLimit-Remaining": "50", "RateLimit-Reset": "30"},
+			expected: Some(RateLimit{
+				Limit:     Some(int64(100)),
+				Remaining: 50,
+				Reset:     Some(30 * time.Second),
+			}),
+		},
+		{
+			name:    "x-ratelimit without limit or reset",
+			headers: map[string]string{"X-RateLimit-Remaining": "0"},
+			expected: Some(RateLimit{
+				Limit:     None[int64](),
+				Remaining: 0,
+				Reset:     None[time.Duration](),
+			}),
+		},
+		{
+			name:    "x-rate-limit epoch reset in the past",
+			headers: map[string]string{"X-Rate-Limit-Limit": "10", "X-Rate-Limit-Remaining": "1", "X-Rate-Limit-Reset": "1700000000"},
+			expected: Some(RateLimit{
+				Limit:     Some(int64(10)),
+				Remaining: 1,
+				Reset:     Some(time.Duration(0)),
+			}),
+		},
+		{
+			name:     "invalid remaining",
+			headers:  map[string]string{"RateLimit-Remaining": "-1"},
+			expected: None[RateLimit](),
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			headers := make(http.Header)
+			for k, v := range tc.headers {
+				headers.Set(k, v)
+			}
+			Equal(t, HasRateLimit(headers), tc.expected)
+		})
+	}
+
+	// epoch reset in the future
+	headers := make(http.Header)
+	headers.Set("X-RateLimit-Remaining", "0")
+	headers.Set("X-RateLimit-Reset", strconv.FormatInt(time.Now().Add(time.Hour).Unix(), 10))
+	rl := HasRateLimit(headers)
+	Equal(t, rl.IsSome(), true)
+	Equal(t, rl.Unwrap().Reset.Unwrap() > 59*time.Minute, true)
+}
+
+func TestRateLimitThrottle(t *testing.T) {
+	throttle := NewRateLimitThrottle(2)
+	Equal(t, throttle.reserve("example.com", time.Now()), time.Duration(0))
+
+	throttle.Observe("example.com", http.Header{"Ratelimit-Remaining": []string{"3"}, "Ratelimit-Reset": []string{"10"}})
+	now := time.Now()
+	Equal(t, throttle.reserve("other.com", now), time.Duration(0))
+	Equal(t, throttle.reserve("example.com", now), time.Duration(0))
+
+	wait := throttle.reserve("example.com", now)
+	Equal(t, wait > 3*time.Second && wait <= 10*time.Second/3, true)
+	wait = throttle.reserve("example.com", now)
+	Equal(t, wait > 4*time.Second && wait <= 10*time.Second/2, true)
+
+	// quota exhausted waits for the reset
+	wait = throttle.reserve("example.com", now)
+	Equal(t, wait > 9*time.Second && wait <= 10*time.Second, true)
+
+	// once reset no longer throttled
+	Equal(t, throttle.reserve("example.com", now.Add(11*time.Second)), time.Duration(0))
+
+	throttle.Observe("example.com", http.Header{"Ratelimit-Remaining": []string{"0"}, "Ratelimit-Reset": []string{"10"}})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	Equal(t, throttle.Wait(ctx, "example.com"), context.Canceled)
+}
+
+func TestRetryerRateLimit(t *testing.T) {
+	var count int
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		count++
+		if count == 1 {
+			w.Header().Set("RateLimit-Remaining", "0")
+			w.Header().Set("RateLimit-Reset", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	var backoffCalled bool
+	throttle := NewRateLimitThrottle(1)
+	retryer := NewRetryer().
+		Backoff(func(_ context.Context, _ int, _ error) { backoffCalled = true }).
+		RateLimitBackoff(true).
+		Throttle(throttle)
+
+	result := retryer.DoResponse(context.Background(), func(ctx context.Context) Result[*http.Request, error] {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		if err != nil {
+			return Err[*http.Request, error](err)
+		}
+		return Ok[*http.Request, error](req)
+	}, http.StatusOK)
+	Equal(t, result.IsOk(), true)
+	_ = result.Unwrap().Body.Close()
+	Equal(t, count, 2)
+	Equal(t, backoffCalled, false)
+	Equal(t, len(throttle.hosts), 0)
+}
+
+type closeCountingBody struct {
+	io.Reader
+	closes int
+}
+
+func (b *closeCountingBody) Close() error {
+	b.closes++
+	return nil
+}
+
+func TestRetryerThrottleClosesBody(t *testing.T) {
+	throttle := NewRateLimitThrottle(1)
+	throttle.Observe("example.com", http.Header{"Ratelimit-Remaining": []string{"0"}, "Ratelimit-Reset": []string{"10"}})
+	retryer := NewRetryer().Throttle(throttle)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	body := &closeCountingBody{Reader: strings.NewReader("data")}
+	var builds int
+	result := retryer.DoResponse(ctx, func(ctx context.Context) Result[*http.Request, error] {
+		builds++
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, "http://example.com", body)
+		if err != nil {
+			return Err[*http.Request, error](err)
+		}
+		return Ok[*http.Request, error](req)
+	}, http.StatusOK)
+	Equal(t, result.IsErr(), true)
+	Equal(t, builds > 0, true)
+	Equal(t, body.closes, builds)
+}
@@ -0,0 +1,151 @@
+package httpext
+
+import (
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	runtimeext "github.com/go-playground/pkg/v5/runtime"
+)
+
+// PanicReport contains the details of a panic recovered by the `Recoverer` middleware.
+type PanicReport struct {
+	// Value is the value passed to panic.
+	Value interface{}
+
+	// Frames is the stack of the panicking goroutine starting at the function which panicked.
+	Frames []runtimeext.Frame
+
+	// Method is the HTTP method of the request.
+	Method string
+
+	// URL is the request URI of the request.
+	URL string
+
+	// ClientIP is the client IP of the request as returned by `ClientIP`.
+	ClientIP string
+
+	// UserAgent is the User-Agent of the request.
+	UserAgent string
+
+	// HeadersSent indicates the response headers had already been sent and so no error response could be written.
+	HeadersSent bool
+}
+
+// PanicReporterFn is a function called with the details of a recovered panic eg. to log or send to an error
+// tracking service.
+type PanicReporterFn func(r *http.Request, report PanicReport)
+
+// Recoverer is a middleware which recovers panics, reports them and responds with 500 Internal Server Error.
+type Recoverer struct {
+	reporterFn PanicReporterFn
+}
+
+// NewRecoverer returns a new `Recoverer` with sane default values.
+//
+// The default values are:
+//   - `Reporter` is nil and so recovered panics are not reported.
+func NewRecoverer() Recoverer {
+	return Recoverer{}
+}
+
+// Reporter sets the function called with the details of every recovered panic.
+func (rc Recoverer) Reporter(fn PanicReporterFn) Recoverer {
+	rc.reporterFn = fn
+	return rc
+}
+
+// Handler returns an http.Handler which recovers any panic from next.
+//
+// If the response headers have not yet been sent a 500 response is written, as application/problem+json when
+// preferred by the Accept header otherwise application/json. If they have been sent the response is aborted by
+// panicking with http.ErrAbortHandler, after reporting, so that the client does not see a truncated response as
+// successful.
+//
+// A panic with http.ErrAbortHandler is re-panicked without reporting as expected by net/http.
+func (rc Recoverer) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &responseWriter{ResponseWriter: w}
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+			if rc.reporterFn != nil {
+				rc.reporterFn(r, PanicReport{
+					Value:       v,
+					Frames:      panicFrames(),
+					Method:      r.Method,
+					URL:         r.RequestURI,
+					ClientIP:    ClientIP(r),
+					UserAgent:   r.UserAgent(),
+					HeadersSent: rw.wroteHeader,
+				})
+			}
+			if rw.wroteHeader {
+				panic(http.ErrAbortHandler)
+			}
+			problem := ProblemDetails{Status: http.StatusInternalServerError}
+			if prefersProblemJSON(r) {
+				_ = ProblemJSON(w, problem)
+			} else {
+				problem.Title = http.StatusText(problem.Status)
+				_ = JSON(w, problem.Status, problem)
+			}
+		}()
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// panicFrames returns the stack frames of the panicking goroutine starting at the function which panicked, skipping
+// the deferred function and runtime panic machinery.
+func panicFrames() []runtimeext.Frame {
+	frames := runtimeext.Frames(2)
+	for i, f := range frames {
+		if f.Frame.Function == "runtime.gopanic" || strings.HasPrefix(f.Frame.Function, "runtime.panic") {
+			// skip any subsequent runtime frames eg. runtime.panicmem -> runtime.sigpanic
+			for i++; i < len(frames) && strings.HasPrefix(frames[i].Frame.Function, "runtime."); i++ {
+			}
+			return frames[i:]
+		}
+	}
+	return frames
+}
+
+// prefersProblemJSON returns true if the Accept header lists application/problem+json with a quality value at least
+// that of application/json.
+func prefersProblemJSON(r *http.Request) bool {
+	var problemQ, jsonQ float64 = -1, -1
+	for _, value := range r.Header.Values(Accept) {
+		for _, accept := range strings.Split(value, ",") {
+			typ, params, err := mime.ParseMediaType(strings.TrimSpace(accept))
+			if err != nil {
+				continue
+			}
+			q := 1.0
+			if qv, ok := params["q"]; ok {
+				q = parseQuality(qv)
+			}
+			switch typ {
+			case ApplicationProblemJSON:
+				problemQ = q
+			case ApplicationJSONNoCharset:
+				jsonQ = q
+			}
+		}
+	}
+	return problemQ > 0 && problemQ >= jsonQ
+}
+
+// parseQuality parses a quality value returning 0 if invalid.
+func parseQuality(s string) float64 {
+	q, err := strconv.ParseFloat(s, 64)
+	if err != nil || q < 0 || q > 1 {
+		return 0
+	}
+	return q
+}
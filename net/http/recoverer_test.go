@@ -0,0 +1,102 @@
+package httpext
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/go-playground/assert/v2"
+)
+
+func panicHandler(w http.ResponseWriter, r *http.Request) {
+	if r.URL.Query().Get("write") != "" {
+		w.WriteHeader(http.StatusOK)
+	}
+	panic("boom")
+}
+
+func TestRecoverer(t *testing.T) {
+	var report PanicReport
+	handler := NewRecoverer().
+		Reporter(func(_ *http.Request, r PanicReport) { report = r }).
+		Handler(http.HandlerFunc(panicHandler))
+
+	tests := []struct {
+		name                string
+		accept              string
+		expectedContentType string
+	}{
+		{
+			name:                "json",
+			expectedContentType: ApplicationJSON,
+		},
+		{
+			name:                "problem-json",
+			accept:              "application/json;q=0.5, application/problem+json",
+			expectedContentType: ApplicationProblemJSON,
+		},
+		{
+			name:                "json preferred",
+			accept:              "application/problem+json;q=0.5, application/json",
+			expectedContentType: ApplicationJSON,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			report = PanicReport{}
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			req.Header.Set(Accept, tc.accept)
+			req.Header.Set(UserAgent, "test-agent")
+			req.RemoteAddr = "10.0.0.1:1234"
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+
+			Equal(t, w.Code, http.StatusInternalServerError)
+			Equal(t, w.Header().Get(ContentType), tc.expectedContentType)
+			var problem ProblemDetails
+			Equal(t, json.Unmarshal(w.Body.Bytes(), &problem), nil)
+			Equal(t, problem.Status, http.StatusInternalServerError)
+			Equal(t, problem.Title, "Internal Server Error")
+
+			Equal(t, report.Value, "boom")
+			Equal(t, report.Method, http.MethodGet)
+			Equal(t, report.URL, "/test")
+			Equal(t, report.ClientIP, "10.0.0.1")
+			Equal(t, report.UserAgent, "test-agent")
+			Equal(t, report.HeadersSent, false)
+			Equal(t, report.Frames[0].Function(), "panicHandler")
+		})
+	}
+}
+
+func TestRecovererHeadersSent(t *testing.T) {
+	var report PanicReport
+	handler := NewRecoverer().
+		Reporter(func(_ *http.Request, r PanicReport) { report = r }).
+		Handler(http.HandlerFunc(panicHandler))
+
+	defer func() {
+		Equal(t, recover(), http.ErrAbortHandler)
+		Equal(t, report.HeadersSent, true)
+		Equal(t, report.Value, "boom")
+	}()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/?write=true", nil))
+}
+
+func TestRecovererAbortHandler(t *testing.T) {
+	var reported bool
+	handler := NewRecoverer().
+		Reporter(func(_ *http.Request, _ PanicReport) { reported = true }).
+		Handler(http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+			panic(http.ErrAbortHandler)
+		}))
+
+	defer func() {
+		Equal(t, recover(), http.ErrAbortHandler)
+		Equal(t, reported, false)
+	}()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/", nil))
+}
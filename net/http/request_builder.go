@@ -0,0 +1,198 @@
+//go:build go1.18
+// +build go1.18
+
+package httpext
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"os"
+
+	. "github.com/go-playground/pkg/v5/values/result"
+)
+
+// RequestBuilder is used to create a `BuildRequestFn2` which builds a fresh request, with a fresh body, for every
+// attempt made by the `Retryer`.
+//
+// Every request built sets the `GetBody`, `Content-Type` and, when known, `Content-Length` so that bodies are never
+// reused once consumed.
+//
+// The `RequestBuilder` is designed to be stateless and reusable. Configuration is also copy and so a base
+// `RequestBuilder` can be used and changed for one-off requests.
+type RequestBuilder struct {
+	method string
+	url    string
+	header http.Header
+	gzip   bool
+}
+
+// NewRequestBuilder returns a new `RequestBuilder` for the provided method and url.
+func NewRequestBuilder(method, url string) RequestBuilder {
+	return RequestBuilder{
+		method: method,
+		url:    url,
+	}
+}
+
+// Header sets a header on every request built, overriding any header set by the builder itself eg. Content-Type.
+func (b RequestBuilder) Header(key, value string) RequestBuilder {
+	header := b.header.Clone()
+	if header == nil {
+		header = make(http.Header)
+	}
+	header.Set(key, value)
+	b.header = header
+	return b
+}
+
+// Gzip sets whether the request body should be gzip compressed and the Content-Encoding header set.
+func (b RequestBuilder) Gzip(enabled bool) RequestBuilder {
+	b.gzip = enabled
+	return b
+}
+
+// NoBody returns a `BuildRequestFn2` building a request without a body.
+func (b RequestBuilder) NoBody() BuildRequestFn2 {
+	return func(ctx context.Context) Result[*http.Request, error] {
+		req, err := http.NewRequestWithContext(ctx, b.method, b.url, nil)
+		if err != nil {
+			return Err[*http.Request, error](err)
+		}
+		b.setHeaders(req, "", false)
+		return Ok[*http.Request, error](req)
+	}
+}
+
+// JSON returns a `BuildRequestFn2` building a request with the JSON encoding of v as the body.
+//
+// v is encoded once, upon calling this function, and the resulting bytes reused for every attempt.
+func (b RequestBuilder) JSON(v any) BuildRequestFn2 {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return errRequestFn(err)
+	}
+	return b.Bytes(ApplicationJSON, data)
+}
+
+// XML returns a `BuildRequestFn2` building a request with the XML encoding of v as the body.
+//
+// v is encoded once, upon calling this function, and the resulting bytes reused for every attempt.
+func (b RequestBuilder) XML(v any) BuildRequestFn2 {
+	data, err := xml.Marshal(v)
+	if err != nil {
+		return errRequestFn(err)
+	}
+	return b.Bytes(ApplicationXML, append(xmlHeaderBytes[:len(xmlHeaderBytes):len(xmlHeaderBytes)], data...))
+}
+
+// Form returns a `BuildRequestFn2` building a request with the url encoded form encoding of v, using the
+// `DefaultFormEncoder`, as the body.
+//
+// v is encoded once, upon calling this function, and the resulting bytes reused for every attempt.
+func (b RequestBuilder) Form(v any) BuildRequestFn2 {
+	values, err := DefaultFormEncoder.Encode(v)
+	if err != nil {
+		return errRequestFn(err)
+	}
+	return b.Bytes(ApplicationForm, []byte(values.Encode()))
+}
+
+// Bytes returns a `BuildRequestFn2` building a request with the provided bytes as the body.
+func (b RequestBuilder) Bytes(contentType string, data []byte) BuildRequestFn2 {
+	if b.gzip {
+		var buf bytes.Buffer
+		gzw := gzip.NewWriter(&buf)
+		if _, err := gzw.Write(data); err != nil {
+			return errRequestFn(err)
+		}
+		if err := gzw.Close(); err != nil {
+			return errRequestFn(err)
+		}
+		data = buf.Bytes()
+	}
+	return func(ctx context.Context) Result[*http.Request, error] {
+		// http.NewRequest sets both the GetBody and ContentLength for a *bytes.Reader
+		req, err := http.NewRequestWithContext(ctx, b.method, b.url, bytes.NewReader(data))
+		if err != nil {
+			return Err[*http.Request, error](err)
+		}
+		b.setHeaders(req, contentType, b.gzip)
+		return Ok[*http.Request, error](req)
+	}
+}
+
+// File returns a `BuildRequestFn2` building a request with the contents of the file at path as the body. The file
+// is re-opened for every attempt and the Content-Type detected from the file extension.
+//
+// When gzip is enabled the file is compressed while streaming and so the Content-Length is unknown.
+func (b RequestBuilder) File(path string) BuildRequestFn2 {
+	return func(ctx context.Context) Result[*http.Request, error] {
+		body, length, err := b.openFile(path)
+		if err != nil {
+			return Err[*http.Request, error](err)
+		}
+		req, err := http.NewRequestWithContext(ctx, b.method, b.url, body)
+		if err != nil {
+			_ = body.Close()
+			return Err[*http.Request, error](err)
+		}
+		req.ContentLength = length
+		req.GetBody = func() (io.ReadCloser, error) {
+			body, _, err := b.openFile(path)
+			return body, err
+		}
+		b.setHeaders(req, detectContentType(path), b.gzip)
+		return Ok[*http.Request, error](req)
+	}
+}
+
+func (b RequestBuilder) openFile(path string) (io.ReadCloser, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	if b.gzip {
+		pr, pw := io.Pipe()
+		go func() {
+			gzw := gzip.NewWriter(pw)
+			_, err := io.Copy(gzw, f)
+			if err == nil {
+				err = gzw.Close()
+			}
+			_ = f.Close()
+			_ = pw.CloseWithError(err)
+		}()
+		return pr, -1, nil
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, 0, err
+	}
+	return f, fi.Size(), nil
+}
+
+// setHeaders sets the Content-Type, if any, and Content-Encoding, when the body was compressed, before the builders
+// headers so they may be overridden.
+func (b RequestBuilder) setHeaders(req *http.Request, contentType string, compressed bool) {
+	if contentType != "" {
+		req.Header.Set(ContentType, contentType)
+	}
+	if compressed {
+		req.Header.Set(ContentEncoding, Gzip)
+	}
+	for k, v := range b.header {
+		req.Header[k] = append([]string(nil), v...)
+	}
+}
+
+func errRequestFn(err error) BuildRequestFn2 {
+	return func(_ context.Context) Result[*http.Request, error] {
+		return Err[*http.Request, error](err)
+	}
+}
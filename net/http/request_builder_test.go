@@ -0,0 +1,139 @@
+//go:build go1.18
+// +build go1.18
+
+package httpext
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/xml"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+
+	. "github.com/go-playground/assert/v2"
+)
+
+func TestRequestBuilder(t *testing.T) {
+	type Test struct {
+		Name string `json:"name" xml:"name" form:"name"`
+	}
+	readme, err := os.ReadFile("../../README.md")
+	Equal(t, err, nil)
+
+	tests := []struct {
+		name        string
+		fn          func(b RequestBuilder) BuildRequestFn2
+		gzip        bool
+		contentType string
+		expected    string
+	}{
+		{
+			name:        "json",
+			fn:          func(b RequestBuilder) BuildRequestFn2 { return b.JSON(Test{Name: "test"}) },
+			contentType: ApplicationJSON,
+			expected:    `{"name":"test"}`,
+		},
+		{
+			name:        "json-gzip",
+			fn:          func(b RequestBuilder) BuildRequestFn2 { return b.JSON(Test{Name: "test"}) },
+			gzip:        true,
+			contentType: ApplicationJSON,
+			expected:    `{"name":"test"}`,
+		},
+		{
+			name:        "xml",
+			fn:          func(b RequestBuilder) BuildRequestFn2 { return b.XML(Test{Name: "test"}) },
+			contentType: ApplicationXML,
+			expected:    xml.Header + `<Test><name>test</name></Test>`,
+		},
+		{
+			name:        "form",
+			fn:          func(b RequestBuilder) BuildRequestFn2 { return b.Form(Test{Name: "test"}) },
+			contentType: ApplicationForm,
+			expected:    `name=test`,
+		},
+		{
+			name:        "bytes",
+			fn:          func(b RequestBuilder) BuildRequestFn2 { return b.Bytes(TextPlain, []byte("raw")) },
+			contentType: TextPlain,
+			expected:    `raw`,
+		},
+		{
+			name:     "bytes-gzip-no-content-type",
+			fn:       func(b RequestBuilder) BuildRequestFn2 { return b.Bytes("", []byte("raw")) },
+			gzip:     true,
+			expected: `raw`,
+		},
+		{
+			name:        "file",
+			fn:          func(b RequestBuilder) BuildRequestFn2 { return b.File("../../README.md") },
+			contentType: TextMarkdown,
+			expected:    string(readme),
+		},
+		{
+			name:        "file-gzip",
+			fn:          func(b RequestBuilder) BuildRequestFn2 { return b.File("../../README.md") },
+			gzip:        true,
+			contentType: TextMarkdown,
+			expected:    string(readme),
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			var count int
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				Equal(t, r.Header.Get(ContentType), tc.contentType)
+				Equal(t, r.Header.Get("X-Test"), "true")
+
+				var body io.Reader = r.Body
+				if tc.gzip {
+					Equal(t, r.Header.Get(ContentEncoding), Gzip)
+					gzr, err := gzip.NewReader(r.Body)
+					Equal(t, err, nil)
+					body = gzr
+				} else {
+					Equal(t, r.Header.Get(ContentLength), strconv.Itoa(len(tc.expected)))
+				}
+				b, err := io.ReadAll(body)
+				Equal(t, err, nil)
+				Equal(t, string(b), tc.expected)
+
+				// force a retry after the body has been consumed
+				if count == 0 {
+					count++
+					w.WriteHeader(http.StatusServiceUnavailable)
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer server.Close()
+
+			fn := tc.fn(NewRequestBuilder(http.MethodPost, server.URL).Header("X-Test", "true").Gzip(tc.gzip))
+
+			result := NewRetryer().Backoff(nil).DoResponse(context.Background(), fn, http.StatusOK)
+			Equal(t, result.IsOk(), true)
+			_ = result.Unwrap().Body.Close()
+			Equal(t, count, 1)
+
+			req := fn(context.Background())
+			Equal(t, req.IsOk(), true)
+			NotEqual(t, req.Unwrap().GetBody, nil)
+		})
+	}
+}
+
+func TestRequestBuilderErrors(t *testing.T) {
+	b := NewRequestBuilder(http.MethodPost, "http://localhost")
+	Equal(t, b.JSON(func() {})(context.Background()).IsErr(), true)
+	Equal(t, b.File("does-not-exist")(context.Background()).IsErr(), true)
+
+	req := b.NoBody()(context.Background())
+	Equal(t, req.IsOk(), true)
+	Equal(t, req.Unwrap().Header.Get(ContentType), "")
+}
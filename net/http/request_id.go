@@ -0,0 +1,92 @@
+//go:build go1.18
+// +build go1.18
+
+package httpext
+
+import (
+	"context"
+	"net/http"
+
+	. "github.com/go-playground/pkg/v5/values/option"
+)
+
+// maxRequestIDLength is the maximum length of an incoming request ID that will be trusted.
+const maxRequestIDLength = 128
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of the context containing the request ID.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID from the context, if any.
+//
+// The request ID is retained by `contextext.Detach` allowing it to be used for correlating background work.
+func RequestIDFromContext(ctx context.Context) Option[string] {
+	if id, ok := ctx.Value(requestIDKey{}).(string); ok {
+		return Some(id)
+	}
+	return None[string]()
+}
+
+// RequestID is a middleware which reads, or generates, a request ID storing it in the request context, see
+// `RequestIDFromContext`, and echoing it on the response.
+//
+// The `Retryer` automatically propagates the request ID from the context onto outgoing requests.
+type RequestID struct {
+	header      string
+	generatorFn func() string
+}
+
+// NewRequestID returns a new `RequestID` with sane default values.
+//
+// The default values are:
+//   - `Header` is `X-Request-Id`.
+//   - `GeneratorFn` is `NewIdempotencyKey`, generating a random UUID v4.
+func NewRequestID() RequestID {
+	return RequestID{
+		header:      XRequestID,
+		generatorFn: NewIdempotencyKey,
+	}
+}
+
+// Header sets the header the request ID is read from and echoed on.
+func (rid RequestID) Header(header string) RequestID {
+	rid.header = header
+	return rid
+}
+
+// GeneratorFn sets the function used to generate a request ID when the request does not contain a valid one.
+func (rid RequestID) GeneratorFn(fn func() string) RequestID {
+	rid.generatorFn = fn
+	return rid
+}
+
+// Handler returns an http.Handler which sets the request ID before calling next.
+//
+// Incoming request IDs longer than 128 characters or containing anything other than printable ASCII are replaced by
+// a newly generated one to prevent log injection.
+func (rid RequestID) Handler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := r.Header.Get(rid.header)
+		if !isValidRequestID(id) {
+			id = rid.generatorFn()
+			r.Header.Set(rid.header, id)
+		}
+		w.Header().Set(rid.header, id)
+		next.ServeHTTP(w, r.WithContext(ContextWithRequestID(r.Context(), id)))
+	})
+}
+
+func isValidRequestID(id string) bool {
+	if id == "" || len(id) > maxRequestIDLength {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
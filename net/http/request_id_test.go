@@ -0,0 +1,92 @@
+//go:build go1.18
+// +build go1.18
+
+package httpext
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/go-playground/assert/v2"
+	. "github.com/go-playground/pkg/v5/values/option"
+	. "github.com/go-playground/pkg/v5/values/result"
+)
+
+func TestRequestID(t *testing.T) {
+	var id Option[string]
+	handler := NewRequestID().
+		GeneratorFn(func() string { return "generated" }).
+		Handler(http.HandlerFunc(func(_ http.ResponseWriter, r *http.Request) {
+			id = RequestIDFromContext(r.Context())
+		}))
+
+	tests := []struct {
+		name     string
+		incoming string
+		expected string
+	}{
+		{name: "generated", expected: "generated"},
+		{name: "incoming", incoming: "abc-123", expected: "abc-123"},
+		{name: "invalid", incoming: "abc 123", expected: "generated"},
+		{name: "too long", incoming: strings.Repeat("a", maxRequestIDLength+1), expected: "generated"},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			if tc.incoming != "" {
+				req.Header.Set(XRequestID, tc.incoming)
+			}
+			w := httptest.NewRecorder()
+			handler.ServeHTTP(w, req)
+			Equal(t, id, Some(tc.expected))
+			Equal(t, w.Header().Get(XRequestID), tc.expected)
+		})
+	}
+
+	Equal(t, RequestIDFromContext(context.Background()), None[string]())
+}
+
+func TestRetryerRequestID(t *testing.T) {
+	var ids, attempts []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		ids = append(ids, r.Header.Get(XRequestID))
+		attempts = append(attempts, r.Header.Get(XRequestAttempt))
+		if len(ids) == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	retryer := NewRetryer().Backoff(nil).DecodeFn(nil)
+	fn := func(ctx context.Context) Result[*http.Request, error] {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		if err != nil {
+			return Err[*http.Request, error](err)
+		}
+		return Ok[*http.Request, error](req)
+	}
+
+	ctx := ContextWithRequestID(context.Background(), "abc-123")
+	Equal(t, retryer.Do(ctx, fn, nil, http.StatusOK), nil)
+	Equal(t, ids, []string{"abc-123", "abc-123"})
+	Equal(t, attempts, []string{"1", "2"})
+
+	ids, attempts = nil, nil
+	Equal(t, retryer.RequestIDHeaders("", "").Do(ctx, fn, nil, http.StatusOK), nil)
+	Equal(t, ids, []string{"", ""})
+	Equal(t, attempts, []string{"", ""})
+
+	ids, attempts = nil, nil
+	Equal(t, retryer.Do(context.Background(), fn, nil, http.StatusOK), nil)
+	Equal(t, ids, []string{"", ""})
+	Equal(t, attempts, []string{"", ""})
+}
@@ -0,0 +1,76 @@
+package httpext
+
+import (
+	"bufio"
+	"errors"
+	"net"
+	"net/http"
+)
+
+// errHijackNotSupported is returned when hijacking a wrapped http.ResponseWriter which does not support it.
+var errHijackNotSupported = errors.New("http.Hijacker not supported by the underlying http.ResponseWriter")
+
+// responseWriter is the http.ResponseWriter wrapper shared by the middleware which tracks if the response headers
+// have been sent, while forwarding the optional http.Flusher and http.Hijacker interfaces of the underlying writer.
+type responseWriter struct {
+	http.ResponseWriter
+
+	// beforeHeader, if set, is called once just before the response headers are sent.
+	beforeHeader func()
+
+	// writeErr, if set, discards all subsequent writes returning the error.
+	writeErr error
+
+	wroteHeader bool
+}
+
+// writeHeader marks the response headers as sent returning false if they already were.
+func (w *responseWriter) writeHeader() bool {
+	if w.wroteHeader {
+		return false
+	}
+	if w.beforeHeader != nil {
+		w.beforeHeader()
+	}
+	w.wroteHeader = true
+	return true
+}
+
+func (w *responseWriter) WriteHeader(code int) {
+	if w.writeHeader() {
+		w.ResponseWriter.WriteHeader(code)
+	}
+}
+
+func (w *responseWriter) Write(b []byte) (int, error) {
+	if w.writeErr != nil {
+		return 0, w.writeErr
+	}
+	w.writeHeader()
+	return w.ResponseWriter.Write(b)
+}
+
+// Flush implements the http.Flusher interface if supported by the underlying http.ResponseWriter.
+func (w *responseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.writeHeader()
+		f.Flush()
+	}
+}
+
+// Hijack implements the http.Hijacker interface if supported by the underlying http.ResponseWriter, otherwise
+// returning an error.
+func (w *responseWriter) Hijack() (net.Conn, *bufio.ReadWriter, error) {
+	h, ok := w.ResponseWriter.(http.Hijacker)
+	if !ok {
+		return nil, nil, errHijackNotSupported
+	}
+	// the connection is now owned by the caller and nothing more may be written by the middleware
+	w.wroteHeader = true
+	return h.Hijack()
+}
+
+// Unwrap returns the underlying http.ResponseWriter for use with http.ResponseController.
+func (w *responseWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
@@ -0,0 +1,38 @@
+package httpext
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/go-playground/assert/v2"
+)
+
+func TestResponseWriterHijack(t *testing.T) {
+	var handler http.Handler = http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, buf, err := w.(http.Hijacker).Hijack()
+		Equal(t, err, nil)
+		defer func() {
+			_ = conn.Close()
+		}()
+		_, _ = buf.WriteString("HTTP/1.1 200 OK\r\nContent-Length: 8\r\nConnection: close\r\n\r\nhijacked")
+		_ = buf.Flush()
+	})
+	handler = NewRecoverer().Handler(NewBodyLimit(1024).Handler(handler))
+
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	resp, err := http.Post(server.URL, TextPlain, strings.NewReader("body"))
+	Equal(t, err, nil)
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+	Equal(t, resp.StatusCode, http.StatusOK)
+
+	// not supported by the underlying writer
+	rw := &responseWriter{ResponseWriter: httptest.NewRecorder()}
+	_, _, err = rw.Hijack()
+	Equal(t, err, errHijackNotSupported)
+}
@@ -4,11 +4,15 @@
 package httpext
 
 import (
+	"bytes"
 	"context"
 	"errors"
 	"io"
+	"mime"
 	"net/http"
+	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
 	bytesext "github.com/go-playground/pkg/v5/bytes"
@@ -32,13 +36,24 @@ type ErrStatusCode struct {
 
 	// Body is the optional body of the HTTP response.
 	Body []byte
+
+	// Err is the optional typed error decoded from the Body by the `Retryer`s `DecodeErrorFn`.
+	Err error
 }
 
 // Error returns the error message for the status code.
 func (e ErrStatusCode) Error() string {
+	if e.Err != nil {
+		return "status code encountered: " + strconv.Itoa(e.StatusCode) + ": " + e.Err.Error()
+	}
 	return "status code encountered: " + strconv.Itoa(e.StatusCode)
 }
 
+// Unwrap returns the typed error decoded from the Body, if any, allowing it to be retrieved using `errors.As`.
+func (e ErrStatusCode) Unwrap() error {
+	return e.Err
+}
+
 // IsRetryable returns if the provided status code is considered retryable.
 func (e ErrStatusCode) IsRetryable() bool {
 	return e.IsRetryableStatusCode
@@ -50,6 +65,11 @@ type BuildRequestFn2 func(ctx context.Context) Result[*http.Request, error]
 // DecodeAnyFn is a function used to decode the response body into the desired type.
 type DecodeAnyFn func(ctx context.Context, resp *http.Response, maxMemory bytesext.Bytes, v any) error
 
+// DecodeErrorFn is a function used to decode the body of an unexpected status code response into a typed error.
+//
+// A nil error should be returned if the body could not be decoded.
+type DecodeErrorFn func(ctx context.Context, sce ErrStatusCode) error
+
 // IsRetryableStatusCodeFn2 is a function used to determine if the provided status code is considered retryable.
 type IsRetryableStatusCodeFn2 func(ctx context.Context, code int) bool
 
@@ -62,12 +82,22 @@ type Retryer struct {
 	isRetryableStatusCodeFn IsRetryableStatusCodeFn2
 	isEarlyReturnFn         errorsext.EarlyReturnFn[error]
 	decodeFn                DecodeAnyFn
+	decodeErrorFn           DecodeErrorFn
 	backoffFn               errorsext.BackoffFn[error]
+	idempotencyKeyFn        IdempotencyKeyFn
+	requestIDHeader         string
+	attemptHeader           string
+	deadlineHeader          string
+	deadlineMargin          time.Duration
+	throttle                *RateLimitThrottle
 	client                  *http.Client
 	timeout                 time.Duration
 	maxBytes                bytesext.Bytes
 	mode                    errorsext.MaxAttemptsMode
+	idempotency             IdempotencyMode
+	deadlineFormat          TimeoutFormat
 	maxAttempts             uint8
+	rateLimitBackoff        bool
 }
 
 // NewRetryer returns a new `Retryer` with sane default values.
@@ -84,6 +114,13 @@ type Retryer struct {
 //   - `Client` is set to `http.DefaultClient`.
 //   - `MaxBytes` is set to 2MiB.
 //   - `DecodeAnyFn` is set to the existing `DecodeResponseAny` function that supports JSON and XML.
+//   - `Idempotency` is `IdempotentOnly`, ambiguous network errors are only retried for idempotent requests.
+//   - `IdempotencyKeyFn` is nil and so no Idempotency-Key is generated.
+//   - `DecodeErrorFn` is nil and so unexpected status code response bodies are not decoded.
+//   - `RateLimitBackoff` is false.
+//   - `Throttle` is nil and so requests are not pre-emptively delayed.
+//   - `RequestIDHeaders` are `X-Request-Id` and `X-Request-Attempt`.
+//   - `DeadlineHeader` is `X-Request-Timeout` using `TimeoutMilliseconds` with a margin of 100ms.
 //
 // WARNING: The default functions may receive enhancements or fixes in the future which could change their behavior,
 // however every attempt will be made to maintain backwards compatibility or made additive-only if possible.
@@ -132,6 +169,11 @@ func NewRetryer() Retryer {
 			case <-t.C:
 			}
 		},
+		requestIDHeader: XRequestID,
+		attemptHeader:   XRequestAttempt,
+		deadlineHeader:  XRequestTimeout,
+		deadlineFormat:  TimeoutMilliseconds,
+		deadlineMargin:  100 * time.Millisecond,
 	}
 }
 
@@ -171,6 +213,15 @@ func (r Retryer) DecodeFn(fn DecodeAnyFn) Retryer {
 	return r
 }
 
+// DecodeErrorFn sets the function used to decode the body of unexpected status code responses into a typed error,
+// which is set on the returned `ErrStatusCode` and available via `errors.As`.
+//
+// See `ErrorDecoder` for a generic content type aware implementation.
+func (r Retryer) DecodeErrorFn(fn DecodeErrorFn) Retryer {
+	r.decodeErrorFn = fn
+	return r
+}
+
 // MaxAttempts sets the maximum number of attempts for the `Retryer`.
 //
 // NOTE: Max attempts is optional and if not set will retry indefinitely on retryable errors.
@@ -185,6 +236,22 @@ func (r Retryer) Backoff(fn errorsext.BackoffFn[error]) Retryer {
 	return r
 }
 
+// RateLimitBackoff sets whether to wait until the rate limit resets, instead of calling the `BackoffFn`, when a
+// retryable status code response indicates the remaining quota is zero. See `HasRateLimit` for the supported headers.
+func (r Retryer) RateLimitBackoff(enabled bool) Retryer {
+	r.rateLimitBackoff = enabled
+	return r
+}
+
+// Throttle sets the `RateLimitThrottle` used to pre-emptively delay requests when the rate limit quota of the host
+// being called is almost exhausted.
+//
+// A nil throttle, the default, disables throttling.
+func (r Retryer) Throttle(t *RateLimitThrottle) Retryer {
+	r.throttle = t
+	return r
+}
+
 // MaxBytes sets the maximum memory to use when decoding the response body including:
 // - upon unexpected status codes.
 // - when decoding the response body.
@@ -195,6 +262,46 @@ func (r Retryer) MaxBytes(i bytesext.Bytes) Retryer {
 
 }
 
+// Idempotency sets how ambiguous network errors, where the server may have already processed the request, are
+// treated for non-idempotent requests eg. POST and PATCH.
+//
+// Errors which occur while establishing a connection are always considered safe to retry.
+func (r Retryer) Idempotency(mode IdempotencyMode) Retryer {
+	r.idempotency = mode
+	return r
+}
+
+// IdempotencyKeyFn sets the function used to generate an Idempotency-Key. When set a key is generated once per `Do`
+// or `DoResponse` call and set on every attempt of a non-idempotent request that does not already have one, allowing
+// it to be safely retried.
+//
+// A nil function, the default, disables generation.
+func (r Retryer) IdempotencyKeyFn(fn IdempotencyKeyFn) Retryer {
+	r.idempotencyKeyFn = fn
+	return r
+}
+
+// RequestIDHeaders sets the headers used to propagate the request ID, set by the `RequestID` middleware or
+// `ContextWithRequestID`, and the attempt number, starting at 1, onto outgoing requests allowing calls to be
+// correlated across services. Headers already set on the request are not overwritten.
+//
+// An empty idHeader disables propagation and an empty attemptHeader disables only the attempt number.
+func (r Retryer) RequestIDHeaders(idHeader, attemptHeader string) Retryer {
+	r.requestIDHeader, r.attemptHeader = idHeader, attemptHeader
+	return r
+}
+
+// DeadlineHeader sets the header, and its format, used to stamp the remaining context budget, minus the safety
+// margin, onto outgoing requests allowing downstream services, using the `Deadline` middleware, to stop work the
+// caller has already abandoned. The header is only set when the context has a deadline and is not already set on the
+// request. When less than the margin remains the remaining budget is stamped as is.
+//
+// An empty header disables stamping.
+func (r Retryer) DeadlineHeader(header string, format TimeoutFormat, margin time.Duration) Retryer {
+	r.deadlineHeader, r.deadlineFormat, r.deadlineMargin = header, format, margin
+	return r
+}
+
 // Timeout sets the timeout for the `Retryer`. This is the timeout per `RetyableFn` attempt and not the entirety
 // of the `Retryer` execution.
 //
@@ -210,19 +317,11 @@ func (r Retryer) Timeout(timeout time.Duration) Retryer {
 //
 // NOTE: it is up to the caller to close the response body if a successful request is made.
 func (r Retryer) DoResponse(ctx context.Context, fn BuildRequestFn2, expectedResponseCodes ...int) Result[*http.Response, error] {
-	return errorsext.NewRetryer[*http.Response, error]().
-		IsRetryableFn(r.isRetryableFn).
-		MaxAttempts(r.mode, r.maxAttempts).
-		Backoff(r.backoffFn).
-		Timeout(r.timeout).
-		IsEarlyReturnFn(r.isEarlyReturnFn).
-		Do(ctx, func(ctx context.Context) Result[*http.Response, error] {
-			req := fn(ctx)
-			if req.IsErr() {
-				return Err[*http.Response, error](req.Err())
-			}
+	st := r.newSendState()
 
-			resp, err := r.client.Do(req.Unwrap())
+	return retryer[*http.Response](r).
+		Do(ctx, func(ctx context.Context) Result[*http.Response, error] {
+			resp, err := r.send(ctx, fn, st, r.client.Do)
 			if err != nil {
 				return Err[*http.Response, error](err)
 			}
@@ -233,14 +332,9 @@ func (r Retryer) DoResponse(ctx context.Context, fn BuildRequestFn2, expectedRes
 						goto RETURN
 					}
 				}
-				b, _ := io.ReadAll(ioext.LimitReader(resp.Body, r.maxBytes))
+				err = r.statusCodeErr(ctx, resp)
 				_ = resp.Body.Close()
-				return Err[*http.Response, error](ErrStatusCode{
-					StatusCode:            resp.StatusCode,
-					IsRetryableStatusCode: r.isRetryableStatusCodeFn(ctx, resp.StatusCode),
-					Headers:               resp.Header,
-					Body:                  b,
-				})
+				return Err[*http.Response, error](err)
 			}
 
 		RETURN:
@@ -251,19 +345,11 @@ func (r Retryer) DoResponse(ctx context.Context, fn BuildRequestFn2, expectedRes
 // Do will execute the provided functions code and automatically retry using the provided retry function decoding
 // the response body into the desired type `v`, which must be passed as mutable.
 func (r Retryer) Do(ctx context.Context, fn BuildRequestFn2, v any, expectedResponseCodes ...int) error {
-	result := errorsext.NewRetryer[typesext.Nothing, error]().
-		IsRetryableFn(r.isRetryableFn).
-		MaxAttempts(r.mode, r.maxAttempts).
-		Backoff(r.backoffFn).
-		Timeout(r.timeout).
-		IsEarlyReturnFn(r.isEarlyReturnFn).
-		Do(ctx, func(ctx context.Context) Result[typesext.Nothing, error] {
-			req := fn(ctx)
-			if req.IsErr() {
-				return Err[typesext.Nothing, error](req.Err())
-			}
+	st := r.newSendState()
 
-			resp, err := r.client.Do(req.Unwrap())
+	result := retryer[typesext.Nothing](r).
+		Do(ctx, func(ctx context.Context) Result[typesext.Nothing, error] {
+			resp, err := r.send(ctx, fn, st, r.client.Do)
 			if err != nil {
 				return Err[typesext.Nothing, error](err)
 			}
@@ -279,13 +365,7 @@ func (r Retryer) Do(ctx context.Context, fn BuildRequestFn2, v any, expectedResp
 					}
 				}
 
-				b, _ := io.ReadAll(ioext.LimitReader(resp.Body, r.maxBytes))
-				return Err[typesext.Nothing, error](ErrStatusCode{
-					StatusCode:            resp.StatusCode,
-					IsRetryableStatusCode: r.isRetryableStatusCodeFn(ctx, resp.StatusCode),
-					Headers:               resp.Header,
-					Body:                  b,
-				})
+				return Err[typesext.Nothing, error](r.statusCodeErr(ctx, resp))
 			}
 
 		DECODE:
@@ -299,3 +379,162 @@ func (r Retryer) Do(ctx context.Context, fn BuildRequestFn2, v any, expectedResp
 	}
 	return nil
 }
+
+// retryer returns the underlying `errorsext.Retryer` configured from the `Retryer` and wrapped to never retry an
+// ErrNonIdempotentRequest.
+func retryer[T any](r Retryer) errorsext.Retryer[T, error] {
+	return errorsext.NewRetryer[T, error]().
+		IsRetryableFn(func(ctx context.Context, err error) bool {
+			var nie ErrNonIdempotentRequest
+			if errors.As(err, &nie) || r.isRetryableFn == nil {
+				return false
+			}
+			return r.isRetryableFn(ctx, err)
+		}).
+		MaxAttempts(r.mode, r.maxAttempts).
+		Backoff(func(ctx context.Context, attempt int, err error) {
+			if r.rateLimitBackoff {
+				if wait := rateLimitWait(err); wait.IsSome() {
+					t := time.NewTimer(wait.Unwrap())
+					defer t.Stop()
+					select {
+					case <-ctx.Done():
+					case <-t.C:
+					}
+					return
+				}
+			}
+			if r.backoffFn != nil {
+				r.backoffFn(ctx, attempt, err)
+			}
+		}).
+		Timeout(r.timeout).
+		IsEarlyReturnFn(func(ctx context.Context, err error) bool {
+			var nie ErrNonIdempotentRequest
+			if errors.As(err, &nie) {
+				return true
+			}
+			return r.isEarlyReturnFn != nil && r.isEarlyReturnFn(ctx, err)
+		})
+}
+
+// ErrorDecoder returns a `DecodeErrorFn` which decodes the unexpected status code response body into the error type
+// `E`, dispatching on the responses Content-Type, supporting JSON and XML including their structured syntax suffixes
+// eg. "application/problem+json".
+//
+// `E` may be a value or pointer type implementing the error interface.
+func ErrorDecoder[E error]() DecodeErrorFn {
+	return func(_ context.Context, sce ErrStatusCode) error {
+		var e E
+		if err := decodeBytes(sce.Headers.Get(ContentType), sce.Body, &e); err != nil {
+			return nil
+		}
+		// guard against returning a typed nil pointer eg. when the body is JSON null
+		if v := reflect.ValueOf(&e).Elem(); v.Kind() == reflect.Ptr && v.IsNil() {
+			return nil
+		}
+		return e
+	}
+}
+
+func decodeBytes(contentType string, b []byte, v any) error {
+	typ, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return err
+	}
+	// decoded using the same helpers as response bodies so that the charset is honoured
+	headers := http.Header{ContentType: []string{contentType}}
+	switch {
+	case typ == nakedApplicationJSON || strings.HasSuffix(typ, "+json"):
+		return decodeJSON(headers, bytes.NewReader(b), NoQueryParams, nil, int64(len(b)), v)
+	case typ == nakedApplicationXML || typ == "text/xml" || strings.HasSuffix(typ, "+xml"):
+		return decodeXML(headers, bytes.NewReader(b), NoQueryParams, nil, int64(len(b)), v)
+	default:
+		return errors.New("unsupported content type")
+	}
+}
+
+// statusCodeErr reads the unexpected status code responses body, up to `MaxBytes`, and returns it as an
+// `ErrStatusCode` decoding the typed error if a `DecodeErrorFn` is set.
+func (r Retryer) statusCodeErr(ctx context.Context, resp *http.Response) error {
+	b, _ := io.ReadAll(ioext.LimitReader(resp.Body, r.maxBytes))
+	sce := ErrStatusCode{
+		StatusCode:            resp.StatusCode,
+		IsRetryableStatusCode: r.isRetryableStatusCodeFn(ctx, resp.StatusCode),
+		Headers:               resp.Header,
+		Body:                  b,
+	}
+	if r.decodeErrorFn != nil && len(b) > 0 {
+		sce.Err = r.decodeErrorFn(ctx, sce)
+	}
+	return sce
+}
+
+// sendState contains the state shared between all attempts of a single `Do` or `DoResponse` call.
+type sendState struct {
+	idempotencyKey string
+	attempt        int
+}
+
+func (r Retryer) newSendState() *sendState {
+	st := new(sendState)
+	if r.idempotencyKeyFn != nil {
+		st.idempotencyKey = r.idempotencyKeyFn()
+	}
+	return st
+}
+
+// send builds and sends a single attempts request using the provided do function, applying the idempotency key,
+// request ID and policy.
+func (r Retryer) send(ctx context.Context, fn BuildRequestFn2, st *sendState, do func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	st.attempt++
+	result := fn(ctx)
+	if result.IsErr() {
+		return nil, result.Err()
+	}
+	req := result.Unwrap()
+
+	if st.idempotencyKey != "" && !isIdempotentRequest(req) {
+		req.Header.Set(IdempotencyKey, st.idempotencyKey)
+	}
+	if r.requestIDHeader != "" {
+		if id := RequestIDFromContext(ctx); id.IsSome() && req.Header.Get(r.requestIDHeader) == "" {
+			req.Header.Set(r.requestIDHeader, id.Unwrap())
+			if r.attemptHeader != "" {
+				req.Header.Set(r.attemptHeader, strconv.Itoa(st.attempt))
+			}
+		}
+	}
+	if r.throttle != nil {
+		if err := r.throttle.Wait(ctx, req.URL.Host); err != nil {
+			// the body must always be closed, as do would have, to release any streaming body goroutines and files
+			if req.Body != nil {
+				_ = req.Body.Close()
+			}
+			return nil, err
+		}
+	}
+	// stamped after any throttling so the remaining budget is accurate
+	if r.deadlineHeader != "" && req.Header.Get(r.deadlineHeader) == "" {
+		if deadline, ok := ctx.Deadline(); ok {
+			remaining := time.Until(deadline)
+			// stamping a zero budget would have the downstream cancel immediately while the caller is still waiting
+			if remaining > r.deadlineMargin {
+				remaining -= r.deadlineMargin
+			}
+			req.Header.Set(r.deadlineHeader, FormatTimeout(remaining, r.deadlineFormat))
+		}
+	}
+
+	resp, err := do(req)
+	if err != nil {
+		if r.idempotency == IdempotentOnly && !isIdempotentRequest(req) && !isUnprocessedError(err) {
+			return nil, ErrNonIdempotentRequest{Method: req.Method, Err: err}
+		}
+		return nil, err
+	}
+	if r.throttle != nil {
+		r.throttle.Observe(req.URL.Host, resp.Header)
+	}
+	return resp, nil
+}
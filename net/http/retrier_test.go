@@ -9,6 +9,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	. "github.com/go-playground/assert/v2"
 	errorsext "github.com/go-playground/pkg/v5/errors"
@@ -189,3 +190,125 @@ func TestRetryer_ExtractStatusBodyEarlyReturn(t *testing.T) {
 	Equal(t, string(esc.Body), http.StatusText(http.StatusUnauthorized))
 	Equal(t, count, 0)
 }
+
+type apiError struct {
+	Code    string `json:"code" xml:"code"`
+	Message string `json:"message" xml:"message"`
+}
+
+func (e *apiError) Error() string {
+	return e.Code + ": " + e.Message
+}
+
+func TestRetryer_DecodeErrorFn(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name     string
+		handler  http.HandlerFunc
+		expected *apiError
+	}{
+		{
+			name: "json",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_ = JSON(w, http.StatusBadRequest, apiError{Code: "invalid", Message: "bad json"})
+			},
+			expected: &apiError{Code: "invalid", Message: "bad json"},
+		},
+		{
+			name: "problem-json",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set(ContentType, "application/problem+json")
+				w.WriteHeader(http.StatusBadRequest)
+				_, _ = w.Write([]byte(`{"code":"invalid","message":"bad problem"}`))
+			},
+			expected: &apiError{Code: "invalid", Message: "bad problem"},
+		},
+		{
+			name: "xml",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				_ = XML(w, http.StatusBadRequest, apiError{Code: "invalid", Message: "bad xml"})
+			},
+			expected: &apiError{Code: "invalid", Message: "bad xml"},
+		},
+		{
+			name: "unsupported",
+			handler: func(w http.ResponseWriter, r *http.Request) {
+				http.Error(w, "bad text", http.StatusBadRequest)
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			server := httptest.NewServer(tc.handler)
+			defer server.Close()
+
+			retryer := NewRetryer().DecodeErrorFn(ErrorDecoder[*apiError]())
+			err := retryer.Do(ctx, NewRequestBuilder(http.MethodGet, server.URL).NoBody(), nil, http.StatusOK)
+			NotEqual(t, err, nil)
+
+			var sce ErrStatusCode
+			Equal(t, errors.As(err, &sce), true)
+			Equal(t, sce.StatusCode, http.StatusBadRequest)
+			NotEqual(t, len(sce.Body), 0)
+			NotEqual(t, sce.Headers.Get(ContentType), "")
+
+			var ae *apiError
+			Equal(t, errors.As(err, &ae), tc.expected != nil)
+			if tc.expected != nil {
+				Equal(t, ae, tc.expected)
+			}
+		})
+	}
+}
+
+func TestRetryer_DeadlineHeader(t *testing.T) {
+	var values []string
+	mux := http.NewServeMux()
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		values = append(values, r.Header.Get(XRequestTimeout), r.Header.Get(GRPCTimeout))
+		w.WriteHeader(http.StatusOK)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	fn := func(ctx context.Context) Result[*http.Request, error] {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		if err != nil {
+			return Err[*http.Request, error](err)
+		}
+		return Ok[*http.Request, error](req)
+	}
+	retryer := NewRetryer().DecodeFn(nil)
+
+	Equal(t, retryer.Do(context.Background(), fn, nil, http.StatusOK), nil)
+	Equal(t, values, []string{"", ""})
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	values = nil
+	Equal(t, retryer.Do(ctx, fn, nil, http.StatusOK), nil)
+	timeout, err := ParseTimeout(values[0], TimeoutMilliseconds)
+	Equal(t, err, nil)
+	Equal(t, timeout <= time.Minute-100*time.Millisecond && timeout > 59*time.Second, true)
+
+	values = nil
+	Equal(t, retryer.DeadlineHeader(GRPCTimeout, TimeoutGRPC, time.Second).Do(ctx, fn, nil, http.StatusOK), nil)
+	Equal(t, values[0], "")
+	timeout, err = ParseTimeout(values[1], TimeoutGRPC)
+	Equal(t, err, nil)
+	Equal(t, timeout <= 59*time.Second && timeout > 58*time.Second, true)
+
+	// less than the margin remaining stamps the remaining budget rather than zero
+	ctx, cancel = context.WithTimeout(context.Background(), 500*time.Millisecond)
+	defer cancel()
+
+	values = nil
+	Equal(t, retryer.DeadlineHeader(XRequestTimeout, TimeoutMilliseconds, time.Second).Do(ctx, fn, nil, http.StatusOK), nil)
+	timeout, err = ParseTimeout(values[0], TimeoutMilliseconds)
+	Equal(t, err, nil)
+	Equal(t, timeout <= 500*time.Millisecond && timeout > 400*time.Millisecond, true)
+}
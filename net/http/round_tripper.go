@@ -0,0 +1,107 @@
+//go:build go1.18
+// +build go1.18
+
+package httpext
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+
+	ioext "github.com/go-playground/pkg/v5/io"
+	. "github.com/go-playground/pkg/v5/values/result"
+)
+
+var _ http.RoundTripper = (*retryRoundTripper)(nil)
+
+// RoundTripper returns an http.RoundTripper which transparently applies the `Retryer` policy to every request made
+// through it, allowing any `*http.Client`, including those used by third-party SDKs, to inherit the same retry
+// semantics.
+//
+// Responses with a status code considered retryable by the `IsRetryableStatusCodeFn` are retried, using the
+// `BackoffFn` which is Retry-After aware by default, with intermediate response bodies drained up to `MaxBytes`
+// and closed so that connections are reused. If all attempts are exhausted the final response is returned with
+// its body, limited to `MaxBytes`, intact.
+//
+// Request bodies are rewound using `http.Request.GetBody`; requests with a body but no `GetBody` are sent once
+// without any retries. The `Client`, `DecodeFn` and per attempt `Timeout` of the `Retryer` are not used.
+//
+// If next is nil `http.DefaultTransport` is used.
+func (r Retryer) RoundTripper(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	r.timeout = 0
+	return &retryRoundTripper{retryer: r, next: next}
+}
+
+type retryRoundTripper struct {
+	retryer Retryer
+	next    http.RoundTripper
+}
+
+// errRetryableResponse is used to pass a retryable status code response, with its buffered body, through the
+// retry logic so that it can be returned if no more attempts remain.
+type errRetryableResponse struct {
+	ErrStatusCode
+	resp *http.Response
+}
+
+func (e errRetryableResponse) Unwrap() error {
+	return e.ErrStatusCode
+}
+
+// RoundTrip implements the http.RoundTripper interface.
+func (rt *retryRoundTripper) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return rt.next.RoundTrip(req)
+	}
+
+	r := rt.retryer
+	st := r.newSendState()
+	result := retryer[*http.Response](r).
+		Do(req.Context(), func(ctx context.Context) Result[*http.Response, error] {
+			resp, err := r.send(ctx, func(ctx context.Context) Result[*http.Request, error] {
+				clone := req.Clone(ctx)
+				if st.attempt > 1 && req.GetBody != nil {
+					body, err := req.GetBody()
+					if err != nil {
+						return Err[*http.Request, error](err)
+					}
+					clone.Body = body
+				}
+				return Ok[*http.Request, error](clone)
+			}, st, rt.next.RoundTrip)
+			if err != nil {
+				return Err[*http.Response, error](err)
+			}
+
+			if r.isRetryableStatusCodeFn(ctx, resp.StatusCode) {
+				b, _ := io.ReadAll(ioext.LimitReader(resp.Body, r.maxBytes))
+				if int64(len(b)) > r.maxBytes {
+					b = b[:r.maxBytes]
+				}
+				_, _ = io.Copy(io.Discard, ioext.LimitReader(resp.Body, r.maxBytes))
+				_ = resp.Body.Close()
+				resp.Body = io.NopCloser(bytes.NewReader(b))
+				return Err[*http.Response, error](errRetryableResponse{
+					ErrStatusCode: ErrStatusCode{
+						StatusCode:            resp.StatusCode,
+						IsRetryableStatusCode: true,
+						Headers:               resp.Header,
+						Body:                  b,
+					},
+					resp: resp,
+				})
+			}
+			return Ok[*http.Response, error](resp)
+		})
+	if result.IsErr() {
+		if rr, ok := result.Err().(errRetryableResponse); ok {
+			return rr.resp, nil
+		}
+		return nil, result.Err()
+	}
+	return result.Unwrap(), nil
+}
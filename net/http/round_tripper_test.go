@@ -0,0 +1,95 @@
+//go:build go1.18
+// +build go1.18
+
+package httpext
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/go-playground/assert/v2"
+	errorsext "github.com/go-playground/pkg/v5/errors"
+)
+
+func TestRetryer_RoundTripper(t *testing.T) {
+	var (
+		count  int
+		bodies []string
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		count++
+		if count < 3 {
+			w.Header().Set(RetryAfter, "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			_, _ = w.Write([]byte("slow down"))
+			return
+		}
+		w.WriteHeader(http.StatusCreated)
+		_, _ = w.Write([]byte("created"))
+	}))
+	defer server.Close()
+
+	var backoffs int
+	client := &http.Client{
+		Transport: NewRetryer().Backoff(func(_ context.Context, _ int, _ error) { backoffs++ }).RoundTripper(nil),
+	}
+
+	resp, err := client.Post(server.URL, TextPlain, strings.NewReader("payload"))
+	Equal(t, err, nil)
+	defer resp.Body.Close()
+	Equal(t, resp.StatusCode, http.StatusCreated)
+	b, err := io.ReadAll(resp.Body)
+	Equal(t, err, nil)
+	Equal(t, string(b), "created")
+	Equal(t, backoffs, 2)
+	Equal(t, bodies, []string{"payload", "payload", "payload"})
+}
+
+func TestRetryer_RoundTripperExhausted(t *testing.T) {
+	var count int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		w.WriteHeader(http.StatusServiceUnavailable)
+		_, _ = w.Write([]byte("unavailable"))
+	}))
+	defer server.Close()
+
+	client := &http.Client{
+		Transport: NewRetryer().Backoff(nil).MaxAttempts(errorsext.MaxAttempts, 2).RoundTripper(http.DefaultTransport),
+	}
+
+	resp, err := client.Get(server.URL)
+	Equal(t, err, nil)
+	defer resp.Body.Close()
+	Equal(t, resp.StatusCode, http.StatusServiceUnavailable)
+	b, err := io.ReadAll(resp.Body)
+	Equal(t, err, nil)
+	Equal(t, string(b), "unavailable")
+	Equal(t, count, 2)
+}
+
+func TestRetryer_RoundTripperNonRewindable(t *testing.T) {
+	var count int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		count++
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	client := &http.Client{
+		Transport: NewRetryer().Backoff(nil).RoundTripper(nil),
+	}
+
+	// io.NopCloser prevents http.NewRequest from setting GetBody
+	resp, err := client.Post(server.URL, TextPlain, io.NopCloser(strings.NewReader("payload")))
+	Equal(t, err, nil)
+	_ = resp.Body.Close()
+	Equal(t, resp.StatusCode, http.StatusServiceUnavailable)
+	Equal(t, count, 1)
+}
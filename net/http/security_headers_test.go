@@ -0,0 +1,68 @@
+package httpext
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/go-playground/assert/v2"
+)
+
+func TestCSP(t *testing.T) {
+	csp := NewCSP().
+		DefaultSrc(CSPSelf).
+		ScriptSrc(CSPSelf, CSPNonceSource, "https://cdn.example.com").
+		ObjectSrc(CSPNone).
+		UpgradeInsecureRequests()
+	Equal(t, csp.String(), "default-src 'self'; script-src 'self' 'nonce' https://cdn.example.com; object-src 'none'; upgrade-insecure-requests")
+
+	// replacing a directive retains its position and leaves the original unchanged
+	replaced := csp.DefaultSrc(CSPNone)
+	Equal(t, replaced.String(), "default-src 'none'; script-src 'self' 'nonce' https://cdn.example.com; object-src 'none'; upgrade-insecure-requests")
+	Equal(t, strings.HasPrefix(csp.String(), "default-src 'self';"), true)
+	Equal(t, csp.build("abc"), "default-src 'self'; script-src 'self' 'nonce-abc' https://cdn.example.com; object-src 'none'; upgrade-insecure-requests")
+}
+
+func TestSecurityHeaders(t *testing.T) {
+	var nonce string
+	next := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		nonce = CSPNonce(r.Context())
+	})
+
+	w := httptest.NewRecorder()
+	NewSecurityHeaders().Handler(next).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	Equal(t, w.Header().Get(StrictTransportSecurity), "max-age=63072000; includeSubDomains")
+	Equal(t, w.Header().Get(XFrameOptions), "DENY")
+	Equal(t, w.Header().Get(XContentTypeOptions), "nosniff")
+	Equal(t, w.Header().Get(XXSSProtection), "0")
+	Equal(t, w.Header().Get(CrossOriginResourcePolicy), "same-origin")
+	Equal(t, w.Header().Get(XDNSPrefetchControl), "off")
+	Equal(t, w.Header().Get(ContentSecurityPolicy), "")
+	Equal(t, nonce, "")
+
+	sh := NewSecurityHeaders().
+		StrictTransportSecurity(time.Hour, false, true).
+		FrameOptions("SAMEORIGIN").
+		DNSPrefetchControl("").
+		ContentSecurityPolicy(NewCSP().ScriptSrc(CSPNonceSource), false)
+	w = httptest.NewRecorder()
+	sh.Handler(next).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	Equal(t, w.Header().Get(StrictTransportSecurity), "max-age=3600; preload")
+	Equal(t, w.Header().Get(XFrameOptions), "SAMEORIGIN")
+	Equal(t, len(w.Header().Values(XDNSPrefetchControl)), 0)
+	NotEqual(t, nonce, "")
+	Equal(t, w.Header().Get(ContentSecurityPolicy), "script-src 'nonce-"+nonce+"'")
+
+	first := nonce
+	w = httptest.NewRecorder()
+	sh.Handler(next).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	NotEqual(t, nonce, first)
+
+	w = httptest.NewRecorder()
+	sh.ContentSecurityPolicy(NewCSP().DefaultSrc(CSPSelf), true).Handler(next).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	Equal(t, w.Header().Get(ContentSecurityPolicy), "")
+	Equal(t, w.Header().Get(ContentSecurityPolicyReport), "default-src 'self'")
+	Equal(t, nonce, "")
+}
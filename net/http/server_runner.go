@@ -0,0 +1,206 @@
+package httpext
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	appext "github.com/go-playground/pkg/v5/app"
+	contextext "github.com/go-playground/pkg/v5/context"
+)
+
+// Readiness is an http.Handler reporting whether the application is ready to receive traffic, responding with
+// 200 OK when ready and 503 Service Unavailable otherwise, for use as a load balancer or Kubernetes readiness probe.
+//
+// It starts as not ready and is safe for concurrent use.
+type Readiness struct {
+	ready int32
+}
+
+// NewReadiness returns a new `Readiness` which is not ready.
+func NewReadiness() *Readiness {
+	return new(Readiness)
+}
+
+// SetReady sets whether the application is ready to receive traffic.
+func (r *Readiness) SetReady(ready bool) {
+	var v int32
+	if ready {
+		v = 1
+	}
+	atomic.StoreInt32(&r.ready, v)
+}
+
+// IsReady returns whether the application is ready to receive traffic.
+func (r *Readiness) IsReady() bool {
+	return atomic.LoadInt32(&r.ready) == 1
+}
+
+// ServeHTTP implements the http.Handler interface.
+func (r *Readiness) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	if r.IsReady() {
+		w.WriteHeader(http.StatusOK)
+		return
+	}
+	w.WriteHeader(http.StatusServiceUnavailable)
+}
+
+// ServerRunner runs one or more `http.Server`s until the context is cancelled, or a server fails, and then gracefully
+// shuts them down.
+type ServerRunner struct {
+	servers         []*http.Server
+	readiness       *Readiness
+	shutdownTimeout time.Duration
+	drainDelay      time.Duration
+}
+
+// NewServerRunner returns a new `ServerRunner`, for the provided servers, with sane default values.
+//
+// The default values are:
+//   - `ShutdownTimeout` is 0 and so derived from the `appext.Context` timeout, see `ShutdownTimeout`.
+//   - `DrainDelay` is 0.
+//   - `Readiness` is nil.
+func NewServerRunner(servers ...*http.Server) ServerRunner {
+	return ServerRunner{servers: servers}
+}
+
+// ShutdownTimeout sets the total time budget for the graceful shutdown, including the `DrainDelay`, after which
+// any remaining connections are forcibly closed.
+//
+// When 0, the default, it is derived from the `appext.ShutdownTimeout` of the context, leaving 10% for the remainder
+// of the application to shut down before being forcibly exited, otherwise 30 seconds is used.
+func (s ServerRunner) ShutdownTimeout(timeout time.Duration) ServerRunner {
+	s.shutdownTimeout = timeout
+	return s
+}
+
+// DrainDelay sets how long to wait, after flipping readiness to not ready, before shutting down the servers
+// allowing load balancers time to stop routing new traffic to this instance.
+func (s ServerRunner) DrainDelay(delay time.Duration) ServerRunner {
+	s.drainDelay = delay
+	return s
+}
+
+// Readiness sets the `Readiness` which is set ready once all servers are listening and not ready as soon as
+// shutdown begins.
+func (s ServerRunner) Readiness(readiness *Readiness) ServerRunner {
+	s.readiness = readiness
+	return s
+}
+
+// Run starts listening on all servers and serves them until the context is cancelled, or any server fails, at which
+// point they are all gracefully shut down. It blocks until shutdown is complete.
+//
+// Servers with a TLSConfig containing certificates are served using TLS. Upon shutdown new connections are no longer
+// accepted, idle keep-alive connections are closed and in-flight requests are given until the `ShutdownTimeout` to
+// complete after which their connections are forcibly closed.
+//
+// The first listener, serve or shutdown error is returned, otherwise nil.
+func (s ServerRunner) Run(ctx context.Context) error {
+	listeners := make([]net.Listener, 0, len(s.servers))
+	for _, srv := range s.servers {
+		addr := srv.Addr
+		if addr == "" {
+			addr = ":http"
+			if isTLSServer(srv) {
+				addr = ":https"
+			}
+		}
+		ln, err := net.Listen("tcp", addr)
+		if err != nil {
+			for _, ln := range listeners {
+				_ = ln.Close()
+			}
+			return err
+		}
+		listeners = append(listeners, ln)
+	}
+
+	errCh := make(chan error, len(s.servers))
+	var wg sync.WaitGroup
+	for i, srv := range s.servers {
+		wg.Add(1)
+		go func(srv *http.Server, ln net.Listener) {
+			defer wg.Done()
+			var err error
+			if isTLSServer(srv) {
+				err = srv.ServeTLS(ln, "", "")
+			} else {
+				err = srv.Serve(ln)
+			}
+			if err != nil && !errors.Is(err, http.ErrServerClosed) {
+				errCh <- err
+			}
+		}(srv, listeners[i])
+	}
+	if s.readiness != nil {
+		s.readiness.SetReady(true)
+	}
+
+	var err error
+	select {
+	case <-ctx.Done():
+	case err = <-errCh:
+	}
+	if s.readiness != nil {
+		s.readiness.SetReady(false)
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(contextext.Detach(ctx), s.shutdownBudget(ctx))
+	defer cancel()
+
+	if s.drainDelay > 0 {
+		t := time.NewTimer(s.drainDelay)
+		select {
+		case <-shutdownCtx.Done():
+		case <-t.C:
+		}
+		t.Stop()
+	}
+
+	var m sync.Mutex
+	var swg sync.WaitGroup
+	for _, srv := range s.servers {
+		swg.Add(1)
+		go func(srv *http.Server) {
+			defer swg.Done()
+			srv.SetKeepAlivesEnabled(false)
+			if e := srv.Shutdown(shutdownCtx); e != nil {
+				_ = srv.Close()
+				m.Lock()
+				if err == nil {
+					err = e
+				}
+				m.Unlock()
+			}
+		}(srv)
+	}
+	swg.Wait()
+	wg.Wait()
+
+	if err == nil {
+		select {
+		case err = <-errCh:
+		default:
+		}
+	}
+	return err
+}
+
+func (s ServerRunner) shutdownBudget(ctx context.Context) time.Duration {
+	if s.shutdownTimeout > 0 {
+		return s.shutdownTimeout
+	}
+	if timeout, ok := appext.ShutdownTimeout(ctx); ok {
+		return timeout - timeout/10
+	}
+	return 30 * time.Second
+}
+
+func isTLSServer(srv *http.Server) bool {
+	return srv.TLSConfig != nil && (len(srv.TLSConfig.Certificates) > 0 || srv.TLSConfig.GetCertificate != nil)
+}
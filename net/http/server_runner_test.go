@@ -0,0 +1,120 @@
+package httpext
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	. "github.com/go-playground/assert/v2"
+)
+
+func freeAddr(t *testing.T) string {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer ln.Close()
+	return ln.Addr().String()
+}
+
+func TestServerRunner(t *testing.T) {
+	started := make(chan struct{})
+	release := make(chan struct{})
+	mux := http.NewServeMux()
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-release
+		_, _ = w.Write([]byte("done"))
+	})
+	readiness := NewReadiness()
+	probe := &http.Server{Addr: freeAddr(t), Handler: readiness}
+	api := &http.Server{Addr: freeAddr(t), Handler: mux}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- NewServerRunner(api, probe).Readiness(readiness).ShutdownTimeout(5 * time.Second).Run(ctx)
+	}()
+
+	var resp *http.Response
+	var err error
+	for i := 0; i < 100; i++ {
+		if resp, err = http.Get("http://" + probe.Addr); err == nil {
+			break
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	Equal(t, err, nil)
+	Equal(t, resp.StatusCode, http.StatusOK)
+	_ = resp.Body.Close()
+
+	body := make(chan string, 1)
+	go func() {
+		resp, err := http.Get("http://" + api.Addr + "/slow")
+		if err != nil {
+			body <- err.Error()
+			return
+		}
+		defer resp.Body.Close()
+		b, _ := io.ReadAll(resp.Body)
+		body <- string(b)
+	}()
+	<-started
+
+	cancel()
+	time.Sleep(50 * time.Millisecond)
+	Equal(t, readiness.IsReady(), false)
+
+	// in-flight requests are allowed to complete
+	close(release)
+	Equal(t, <-body, "done")
+	Equal(t, <-done, nil)
+
+	_, err = http.Get("http://" + api.Addr)
+	NotEqual(t, err, nil)
+}
+
+func TestServerRunnerShutdownTimeout(t *testing.T) {
+	started := make(chan struct{})
+	block := make(chan struct{})
+	defer close(block)
+	srv := &http.Server{Addr: freeAddr(t), Handler: http.HandlerFunc(func(_ http.ResponseWriter, _ *http.Request) {
+		close(started)
+		<-block
+	})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		done <- NewServerRunner(srv).ShutdownTimeout(50 * time.Millisecond).Run(ctx)
+	}()
+
+	go func() {
+		for i := 0; i < 100; i++ {
+			if resp, err := http.Get("http://" + srv.Addr); err == nil {
+				_ = resp.Body.Close()
+				return
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}()
+	<-started
+	cancel()
+	Equal(t, <-done, context.DeadlineExceeded)
+}
+
+func TestServerRunnerListenError(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	Equal(t, err, nil)
+	defer ln.Close()
+
+	readiness := NewReadiness()
+	ok := &http.Server{Addr: freeAddr(t)}
+	inUse := &http.Server{Addr: ln.Addr().String()}
+	err = NewServerRunner(ok, inUse).Readiness(readiness).Run(context.Background())
+	NotEqual(t, err, nil)
+	Equal(t, readiness.IsReady(), false)
+}
@@ -0,0 +1,172 @@
+//go:build go1.18
+// +build go1.18
+
+package httpext
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	timeext "github.com/go-playground/pkg/v5/time"
+	. "github.com/go-playground/pkg/v5/values/option"
+)
+
+type serverTimingsKey struct{}
+
+// ServerTimingMetric is a single, named, Server-Timing metric measured using a monotonic `timeext.Instant`.
+type ServerTimingMetric struct {
+	name        string
+	description string
+	start       timeext.Instant
+	duration    time.Duration
+	stopped     bool
+	written     bool
+	timings     *ServerTimings
+}
+
+// Stop stops the metric, if not already stopped, returning its duration. Only stopped metrics are written.
+//
+// Stop is safe to call on a nil metric, as returned by `StartServerTiming` when there is no collector, in which case
+// it returns 0.
+func (m *ServerTimingMetric) Stop() time.Duration {
+	if m == nil {
+		return 0
+	}
+	m.timings.m.Lock()
+	defer m.timings.m.Unlock()
+	if !m.stopped {
+		m.duration, m.stopped = m.start.Elapsed(), true
+	}
+	return m.duration
+}
+
+// ServerTimings is a request scoped collector of Server-Timing metrics, created by the `ServerTimingHandler`
+// middleware and retrieved using `ServerTimingsFromContext`.
+//
+// It is safe for concurrent use allowing metrics to be started and stopped, including nested within one another, from
+// any goroutine handling the request.
+type ServerTimings struct {
+	m       sync.Mutex
+	metrics []*ServerTimingMetric
+}
+
+// ServerTimingsFromContext returns the request scoped `ServerTimings`, if any.
+func ServerTimingsFromContext(ctx context.Context) Option[*ServerTimings] {
+	if st, ok := ctx.Value(serverTimingsKey{}).(*ServerTimings); ok {
+		return Some(st)
+	}
+	return None[*ServerTimings]()
+}
+
+// StartServerTiming starts a new metric on the request scoped `ServerTimings` returning nil, which is safe to
+// `Stop`, if there is none.
+func StartServerTiming(ctx context.Context, name, description string) *ServerTimingMetric {
+	if st := ServerTimingsFromContext(ctx); st.IsSome() {
+		return st.Unwrap().Start(name, description)
+	}
+	return nil
+}
+
+// Start starts a new named metric with an optional description. The name must be a valid HTTP token.
+func (st *ServerTimings) Start(name, description string) *ServerTimingMetric {
+	m := &ServerTimingMetric{
+		name:        name,
+		description: description,
+		start:       timeext.NewInstant(),
+		timings:     st,
+	}
+	st.m.Lock()
+	st.metrics = append(st.metrics, m)
+	st.m.Unlock()
+	return m
+}
+
+// Add adds an already measured metric with an optional description.
+func (st *ServerTimings) Add(name, description string, duration time.Duration) {
+	st.m.Lock()
+	st.metrics = append(st.metrics, &ServerTimingMetric{
+		name:        name,
+		description: description,
+		duration:    duration,
+		stopped:     true,
+		timings:     st,
+	})
+	st.m.Unlock()
+}
+
+// String returns all stopped metrics formatted as a Server-Timing header value.
+func (st *ServerTimings) String() string {
+	st.m.Lock()
+	defer st.m.Unlock()
+	return formatServerTimings(st.metrics)
+}
+
+// unwritten returns the stopped metrics not yet written formatted as a Server-Timing header value and marks them as
+// written.
+func (st *ServerTimings) unwritten() string {
+	st.m.Lock()
+	defer st.m.Unlock()
+	var metrics []*ServerTimingMetric
+	for _, m := range st.metrics {
+		if m.stopped && !m.written {
+			m.written = true
+			metrics = append(metrics, m)
+		}
+	}
+	return formatServerTimings(metrics)
+}
+
+func formatServerTimings(metrics []*ServerTimingMetric) string {
+	var sb strings.Builder
+	for _, m := range metrics {
+		if !m.stopped {
+			continue
+		}
+		if sb.Len() > 0 {
+			sb.WriteString(", ")
+		}
+		sb.WriteString(m.name)
+		sb.WriteString(";dur=")
+		sb.WriteString(strconv.FormatFloat(float64(m.duration.Round(time.Microsecond))/float64(time.Millisecond), 'f', -1, 64))
+		if m.description != "" {
+			sb.WriteString(`;desc="`)
+			for i := 0; i < len(m.description); i++ {
+				if c := m.description[i]; c == '"' || c == '\\' {
+					sb.WriteByte('\\')
+				}
+				sb.WriteByte(m.description[i])
+			}
+			sb.WriteByte('"')
+		}
+	}
+	return sb.String()
+}
+
+// ServerTimingHandler returns an http.Handler which adds a request scoped `ServerTimings` collector to the request
+// context before calling next.
+//
+// The metrics stopped before the response headers are sent are written as the Server-Timing header. Any stopped after
+// the headers were sent, eg. when streaming, are written as a Server-Timing trailer instead.
+func ServerTimingHandler(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		st := new(ServerTimings)
+		tw := &responseWriter{ResponseWriter: w, beforeHeader: func() {
+			if value := st.unwritten(); value != "" {
+				w.Header().Add(ServerTiming, value)
+			}
+		}}
+		next.ServeHTTP(tw, r.WithContext(context.WithValue(r.Context(), serverTimingsKey{}, st)))
+
+		if value := st.unwritten(); value != "" {
+			if tw.wroteHeader {
+				w.Header().Set(http.TrailerPrefix+ServerTiming, value)
+			} else {
+				w.Header().Add(ServerTiming, value)
+			}
+		}
+	})
+}
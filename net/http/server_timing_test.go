@@ -0,0 +1,65 @@
+//go:build go1.18
+// +build go1.18
+
+package httpext
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/go-playground/assert/v2"
+)
+
+func TestServerTimings(t *testing.T) {
+	st := new(ServerTimings)
+	st.Add("db", `query "users"`, 1500*time.Microsecond)
+	running := st.Start("cache", "")
+	Equal(t, st.String(), `db;dur=1.5;desc="query \"users\""`)
+	d := running.Stop()
+	Equal(t, running.Stop(), d)
+	Equal(t, strings.HasPrefix(st.String(), `db;dur=1.5;desc="query \"users\"", cache;dur=`), true)
+
+	// no collector
+	var metric *ServerTimingMetric = StartServerTiming(context.Background(), "noop", "")
+	Equal(t, metric == nil, true)
+	Equal(t, metric.Stop(), time.Duration(0))
+}
+
+func TestServerTimingHandler(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/header", func(w http.ResponseWriter, r *http.Request) {
+		outer := StartServerTiming(r.Context(), "handler", "")
+		inner := StartServerTiming(r.Context(), "db", "")
+		inner.Stop()
+		outer.Stop()
+		w.WriteHeader(http.StatusOK)
+	})
+	mux.HandleFunc("/trailer", func(w http.ResponseWriter, r *http.Request) {
+		ServerTimingsFromContext(r.Context()).Unwrap().Add("before", "", time.Millisecond)
+		stream := StartServerTiming(r.Context(), "stream", "")
+		_, _ = w.Write([]byte("streaming"))
+		w.(http.Flusher).Flush()
+		stream.Stop()
+	})
+	server := httptest.NewServer(ServerTimingHandler(mux))
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/header")
+	Equal(t, err, nil)
+	_ = resp.Body.Close()
+	value := resp.Header.Get(ServerTiming)
+	Equal(t, strings.HasPrefix(value, "handler;dur="), true)
+	Equal(t, strings.Contains(value, ", db;dur="), true)
+
+	resp, err = http.Get(server.URL + "/trailer")
+	Equal(t, err, nil)
+	_, _ = io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	Equal(t, resp.Header.Get(ServerTiming), "before;dur=1")
+	Equal(t, strings.HasPrefix(resp.Trailer.Get(ServerTiming), "stream;dur="), true)
+}
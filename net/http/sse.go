@@ -0,0 +1,255 @@
+package httpext
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	bytesext "github.com/go-playground/pkg/v5/bytes"
+)
+
+var (
+	// ErrStreamingUnsupported is returned when the http.ResponseWriter does not support flushing which is required
+	// for streaming responses.
+	ErrStreamingUnsupported = errors.New("streaming unsupported: http.ResponseWriter does not implement http.Flusher")
+
+	sseFieldReplacer = strings.NewReplacer("\r\n", "", "\r", "", "\n", "")
+	sseDataReplacer  = strings.NewReplacer("\r\n", "\n", "\r", "\n")
+)
+
+// SSEEvent represents a single Server-Sent Event.
+type SSEEvent struct {
+	// ID is the optional event id, which the client will send back in the Last-Event-ID header on reconnect.
+	ID string
+
+	// Event is the optional event type, if empty clients treat it as a "message" event.
+	Event string
+
+	// Retry is the optional reconnection time the client should use.
+	Retry time.Duration
+
+	// Data is the event data, multi-line data is split into multiple data fields automatically.
+	Data string
+}
+
+// SSEWriter writes Server-Sent Events to an http.ResponseWriter, flushing after each write.
+//
+// The SSEWriter is not safe for concurrent use.
+type SSEWriter struct {
+	w       io.Writer
+	flusher http.Flusher
+	buf     bytes.Buffer
+}
+
+// NewSSEWriter sets the required Server-Sent Events headers, writes the 200 status code and returns a new SSEWriter.
+//
+// ErrStreamingUnsupported is returned if the http.ResponseWriter does not support flushing.
+func NewSSEWriter(w http.ResponseWriter) (*SSEWriter, error) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		return nil, ErrStreamingUnsupported
+	}
+	h := w.Header()
+	h.Set(ContentType, TextEventStream)
+	h.Set(CacheControl, "no-cache")
+	h.Set(XAccelBuffering, "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+	return &SSEWriter{w: w, flusher: flusher}, nil
+}
+
+// WriteEvent writes the provided event and flushes it to the client.
+//
+// Any newlines within the ID or Event fields are removed as they would otherwise corrupt the stream.
+func (s *SSEWriter) WriteEvent(e SSEEvent) error {
+	s.buf.Reset()
+	if e.ID != "" {
+		s.buf.WriteString("id: ")
+		s.buf.WriteString(sseFieldReplacer.Replace(e.ID))
+		s.buf.WriteByte('\n')
+	}
+	if e.Event != "" {
+		s.buf.WriteString("event: ")
+		s.buf.WriteString(sseFieldReplacer.Replace(e.Event))
+		s.buf.WriteByte('\n')
+	}
+	if e.Retry > 0 {
+		s.buf.WriteString("retry: ")
+		s.buf.WriteString(strconv.FormatInt(e.Retry.Milliseconds(), 10))
+		s.buf.WriteByte('\n')
+	}
+	for _, line := range strings.Split(sseDataReplacer.Replace(e.Data), "\n") {
+		s.buf.WriteString("data: ")
+		s.buf.WriteString(line)
+		s.buf.WriteByte('\n')
+	}
+	s.buf.WriteByte('\n')
+	return s.flush()
+}
+
+// WriteComment writes a comment line, which is ignored by clients, and flushes it. This is primarily used as a
+// heartbeat to prevent proxies from closing idle connections.
+func (s *SSEWriter) WriteComment(comment string) error {
+	s.buf.Reset()
+	s.buf.WriteString(": ")
+	s.buf.WriteString(sseFieldReplacer.Replace(comment))
+	s.buf.WriteString("\n\n")
+	return s.flush()
+}
+
+func (s *SSEWriter) flush() error {
+	if _, err := s.w.Write(s.buf.Bytes()); err != nil {
+		return err
+	}
+	s.flusher.Flush()
+	return nil
+}
+
+// StreamSSE writes the events received from the provided channel as Server-Sent Events until the channel is
+// closed or the request context ends, sending a heartbeat comment every heartbeat interval when no other event was
+// written.
+//
+// A heartbeat of 0 disables heartbeats. When the request context ends its error is returned.
+func StreamSSE(w http.ResponseWriter, r *http.Request, heartbeat time.Duration, events <-chan SSEEvent) error {
+	sw, err := NewSSEWriter(w)
+	if err != nil {
+		return err
+	}
+	return sw.stream(r.Context(), heartbeat, events)
+}
+
+func (s *SSEWriter) stream(ctx context.Context, heartbeat time.Duration, events <-chan SSEEvent) error {
+	var (
+		ticker *time.Ticker
+		tick   <-chan time.Time
+	)
+	if heartbeat > 0 {
+		ticker = time.NewTicker(heartbeat)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-tick:
+			if err := s.WriteComment("heartbeat"); err != nil {
+				return err
+			}
+		case e, ok := <-events:
+			if !ok {
+				return nil
+			}
+			if err := s.WriteEvent(e); err != nil {
+				return err
+			}
+			// the heartbeat is only needed once the connection has been idle for the full interval
+			if ticker != nil {
+				ticker.Reset(heartbeat)
+			}
+		}
+	}
+}
+
+// SSEReader reads and parses Server-Sent Events from a stream.
+type SSEReader struct {
+	scanner     *bufio.Scanner
+	lastEventID string
+}
+
+// NewSSEReader returns a new SSEReader which parses Server-Sent Events from the response body, limiting each line
+// to maxLineBytes.
+//
+// An error is returned if the response Content-Type is not "text/event-stream". It is up to the caller to close the
+// response body.
+func NewSSEReader(resp *http.Response, maxLineBytes bytesext.Bytes) (*SSEReader, error) {
+	typ := resp.Header.Get(ContentType)
+	if idx := strings.IndexByte(typ, ';'); idx != -1 {
+		typ = typ[:idx]
+	}
+	if !strings.EqualFold(strings.TrimSpace(typ), TextEventStream) {
+		return nil, errors.New("unsupported content type")
+	}
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 4096), int(maxLineBytes))
+	scanner.Split(scanSSELines)
+	return &SSEReader{scanner: scanner}, nil
+}
+
+// Next returns the next event from the stream, io.EOF is returned when the stream ends.
+//
+// Events without data are not dispatched as described by the specification. The ID of the returned event is the
+// last event ID seen on the stream, even if not set on this particular event.
+func (s *SSEReader) Next() (SSEEvent, error) {
+	var (
+		e    SSEEvent
+		data strings.Builder
+	)
+	for s.scanner.Scan() {
+		line := s.scanner.Text()
+		if line == "" {
+			if data.Len() == 0 {
+				e = SSEEvent{}
+				continue
+			}
+			e.ID = s.lastEventID
+			e.Data = strings.TrimSuffix(data.String(), "\n")
+			return e, nil
+		}
+		if line[0] == ':' {
+			continue
+		}
+		field, value := line, ""
+		if idx := strings.IndexByte(line, ':'); idx != -1 {
+			field, value = line[:idx], strings.TrimPrefix(line[idx+1:], " ")
+		}
+		switch field {
+		case "event":
+			e.Event = value
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+		case "id":
+			if strings.IndexByte(value, 0) == -1 {
+				s.lastEventID = value
+			}
+		case "retry":
+			if n, err := strconv.ParseInt(value, 10, 64); err == nil && n >= 0 {
+				e.Retry = time.Duration(n) * time.Millisecond
+			}
+		}
+	}
+	if err := s.scanner.Err(); err != nil {
+		return SSEEvent{}, err
+	}
+	return SSEEvent{}, io.EOF
+}
+
+// scanSSELines is a bufio.SplitFunc splitting lines terminated by CRLF, LF or CR.
+func scanSSELines(data []byte, atEOF bool) (advance int, token []byte, err error) {
+	if atEOF && len(data) == 0 {
+		return 0, nil, nil
+	}
+	if i := bytes.IndexAny(data, "\r\n"); i >= 0 {
+		if data[i] == '\r' {
+			if i+1 == len(data) && !atEOF {
+				// need more data to determine if CRLF
+				return 0, nil, nil
+			}
+			if i+1 < len(data) && data[i+1] == '\n' {
+				return i + 2, data[:i], nil
+			}
+		}
+		return i + 1, data[:i], nil
+	}
+	if atEOF {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
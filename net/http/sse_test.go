@@ -0,0 +1,122 @@
+//go:build go1.18
+// +build go1.18
+
+package httpext
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/go-playground/assert/v2"
+	bytesext "github.com/go-playground/pkg/v5/bytes"
+	. "github.com/go-playground/pkg/v5/values/result"
+)
+
+func TestSSEWriter(t *testing.T) {
+	w := httptest.NewRecorder()
+	sw, err := NewSSEWriter(w)
+	Equal(t, err, nil)
+	Equal(t, w.Header().Get(ContentType), TextEventStream)
+	Equal(t, w.Header().Get(CacheControl), "no-cache")
+
+	err = sw.WriteEvent(SSEEvent{ID: "1\n", Event: "update", Retry: time.Second, Data: "line1\r\nline2\rline3"})
+	Equal(t, err, nil)
+	err = sw.WriteEvent(SSEEvent{Data: ""})
+	Equal(t, err, nil)
+	err = sw.WriteComment("heartbeat")
+	Equal(t, err, nil)
+	Equal(t, w.Body.String(), "id: 1\nevent: update\nretry: 1000\ndata: line1\ndata: line2\ndata: line3\n\ndata: \n\n: heartbeat\n\n")
+	Equal(t, w.Flushed, true)
+}
+
+func TestSSEReader(t *testing.T) {
+	stream := ": comment\r\n" +
+		"retry: 500\r\n" +
+		"event: first\r\n" +
+		"id: 1\r\n" +
+		"data: a\r\n" +
+		"data:b\r\n\r\n" +
+		"event: ignored\n\n" +
+		"data\rdata: c\r\r" +
+		"data: incomplete"
+
+	resp := &http.Response{
+		Header: http.Header{ContentType: []string{TextEventStream + "; charset=utf-8"}},
+		Body:   io.NopCloser(strings.NewReader(stream)),
+	}
+	r, err := NewSSEReader(resp, bytesext.KiB)
+	Equal(t, err, nil)
+
+	e, err := r.Next()
+	Equal(t, err, nil)
+	Equal(t, e, SSEEvent{ID: "1", Event: "first", Retry: 500 * time.Millisecond, Data: "a\nb"})
+
+	e, err = r.Next()
+	Equal(t, err, nil)
+	Equal(t, e, SSEEvent{ID: "1", Data: "\nc"})
+
+	_, err = r.Next()
+	Equal(t, err, io.EOF)
+
+	resp.Header.Set(ContentType, ApplicationJSON)
+	_, err = NewSSEReader(resp, bytesext.KiB)
+	NotEqual(t, err, nil)
+}
+
+func TestStreamSSE(t *testing.T) {
+	events := make(chan SSEEvent)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_ = StreamSSE(w, r, 10*time.Millisecond, events)
+	}))
+	defer server.Close()
+
+	retryer := NewRetryer()
+	result := retryer.DoResponse(context.Background(), func(ctx context.Context) Result[*http.Request, error] {
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		if err != nil {
+			return Err[*http.Request, error](err)
+		}
+		return Ok[*http.Request, error](req)
+	}, http.StatusOK)
+	Equal(t, result.IsOk(), true)
+	resp := result.Unwrap()
+	defer resp.Body.Close()
+
+	r, err := NewSSEReader(resp, bytesext.KiB)
+	Equal(t, err, nil)
+
+	go func() {
+		time.Sleep(30 * time.Millisecond)
+		events <- SSEEvent{ID: "1", Data: "hello\nworld"}
+		close(events)
+	}()
+
+	e, err := r.Next()
+	Equal(t, err, nil)
+	Equal(t, e, SSEEvent{ID: "1", Data: "hello\nworld"})
+
+	_, err = r.Next()
+	Equal(t, err, io.EOF)
+}
+
+func TestStreamSSEHeartbeatIdleOnly(t *testing.T) {
+	events := make(chan SSEEvent)
+	go func() {
+		for i := 0; i < 10; i++ {
+			time.Sleep(10 * time.Millisecond)
+			events <- SSEEvent{Data: "tick"}
+		}
+		close(events)
+	}()
+
+	w := httptest.NewRecorder()
+	err := StreamSSE(w, httptest.NewRequest(http.MethodGet, "/", nil), 50*time.Millisecond, events)
+	Equal(t, err, nil)
+	Equal(t, strings.Count(w.Body.String(), "data: tick\n\n"), 10)
+	Equal(t, strings.Contains(w.Body.String(), ": heartbeat"), false)
+}
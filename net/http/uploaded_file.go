@@ -0,0 +1,118 @@
+package httpext
+
+import (
+	"io"
+	"mime/multipart"
+	"net/http"
+	"reflect"
+)
+
+var (
+	fileHeaderType    = reflect.TypeOf((*multipart.FileHeader)(nil))
+	fileHeadersType   = reflect.TypeOf([]*multipart.FileHeader(nil))
+	uploadedFileType  = reflect.TypeOf(UploadedFile{})
+	uploadedFilesType = reflect.TypeOf([]UploadedFile(nil))
+)
+
+// UploadedFile is a file of a parsed multipart form, bound by `DecodeMultipartForm` to struct fields by form tag name.
+type UploadedFile struct {
+	// FileName is the client supplied file name.
+	FileName string
+
+	// ContentType is the sniffed content type of the file, not the client supplied one.
+	ContentType string
+
+	// Size is the size of the file in bytes.
+	Size int64
+
+	// Header is the underlying file header of the parsed multipart form.
+	Header *multipart.FileHeader
+}
+
+// Open opens the uploaded file for reading.
+func (f UploadedFile) Open() (multipart.File, error) {
+	return f.Header.Open()
+}
+
+// NewUploadedFile returns an `UploadedFile` for the file header sniffing its content type from the first 512 bytes.
+func NewUploadedFile(fh *multipart.FileHeader) (UploadedFile, error) {
+	file, err := fh.Open()
+	if err != nil {
+		return UploadedFile{}, err
+	}
+	defer func() {
+		_ = file.Close()
+	}()
+
+	sniff := make([]byte, sniffLen)
+	n, err := io.ReadFull(file, sniff)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return UploadedFile{}, err
+	}
+	return UploadedFile{
+		FileName:    fh.Filename,
+		ContentType: http.DetectContentType(sniff[:n]),
+		Size:        fh.Size,
+		Header:      fh,
+	}, nil
+}
+
+// bindMultipartFiles sets the top level struct fields of v of type *multipart.FileHeader, []*multipart.FileHeader,
+// UploadedFile, *UploadedFile and []UploadedFile from the files of the form matching their form tag name.
+func bindMultipartFiles(form *multipart.Form, v interface{}) error {
+	if form == nil || len(form.File) == 0 {
+		return nil
+	}
+	val := reflect.ValueOf(v)
+	for val.Kind() == reflect.Ptr || val.Kind() == reflect.Interface {
+		if val.IsNil() {
+			return nil
+		}
+		val = val.Elem()
+	}
+	if val.Kind() != reflect.Struct {
+		return nil
+	}
+	typ := val.Type()
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name, ok := formFieldName(field)
+		if !ok {
+			continue
+		}
+		headers := form.File[name]
+		if len(headers) == 0 {
+			continue
+		}
+
+		fv := val.Field(i)
+		switch field.Type {
+		case fileHeaderType:
+			fv.Set(reflect.ValueOf(headers[0]))
+		case fileHeadersType:
+			fv.Set(reflect.ValueOf(headers))
+		case uploadedFileType, reflect.PtrTo(uploadedFileType):
+			uf, err := NewUploadedFile(headers[0])
+			if err != nil {
+				return err
+			}
+			if field.Type.Kind() == reflect.Ptr {
+				fv.Set(reflect.ValueOf(&uf))
+			} else {
+				fv.Set(reflect.ValueOf(uf))
+			}
+		case uploadedFilesType:
+			files := make([]UploadedFile, 0, len(headers))
+			for _, fh := range headers {
+				uf, err := NewUploadedFile(fh)
+				if err != nil {
+					return err
+				}
+				files = append(files, uf)
+			}
+			fv.Set(reflect.ValueOf(files))
+		}
+	}
+	return nil
+}
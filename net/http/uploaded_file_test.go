@@ -0,0 +1,53 @@
+package httpext
+
+import (
+	"bytes"
+	"io"
+	"mime/multipart"
+	"testing"
+
+	. "github.com/go-playground/assert/v2"
+)
+
+func TestDecodeMultipartFormFiles(t *testing.T) {
+	type TestStruct struct {
+		Name     string                  `form:"name"`
+		Avatar   UploadedFile            `form:"avatar"`
+		Document *UploadedFile           `form:"document"`
+		Raw      *multipart.FileHeader   `form:"raw"`
+		Many     []*multipart.FileHeader `form:"raw"`
+		All      []UploadedFile          `form:"avatar"`
+		Missing  *UploadedFile           `form:"missing"`
+		Ignored  *multipart.FileHeader   `form:"-"`
+	}
+	png := append([]byte("\x89PNG\x0D\x0A\x1A\x0A"), bytes.Repeat([]byte{0}, 1024)...)
+
+	r := newMultipartRequest(t, "/", map[string]string{"name": "joeybloggs"}, map[string][]byte{
+		"avatar":   png,
+		"document": []byte("plain text"),
+		"raw":      []byte("raw"),
+	})
+
+	var test TestStruct
+	err := DecodeMultipartForm(r, NoQueryParams, 10<<20, &test)
+	Equal(t, err, nil)
+	Equal(t, test.Name, "joeybloggs")
+	Equal(t, test.Avatar.FileName, "avatar.bin")
+	Equal(t, test.Avatar.ContentType, ImagePNG)
+	Equal(t, test.Avatar.Size, int64(len(png)))
+	Equal(t, len(test.All), 1)
+	Equal(t, test.All[0].ContentType, ImagePNG)
+	NotEqual(t, test.Document, nil)
+	Equal(t, test.Document.ContentType, TextPlain)
+	Equal(t, test.Raw.Filename, "raw.bin")
+	Equal(t, len(test.Many), 1)
+	Equal(t, test.Missing == nil, true)
+	Equal(t, test.Ignored == nil, true)
+
+	f, err := test.Avatar.Open()
+	Equal(t, err, nil)
+	b, err := io.ReadAll(f)
+	Equal(t, err, nil)
+	Equal(t, b, png)
+	_ = f.Close()
+}
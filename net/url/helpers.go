@@ -7,6 +7,17 @@ import (
 )
 
 // EncodeToURLValues encodes a struct or field into a set of url.Values
+//
+// Fields which encode to no values, such as None Option fields, are omitted.
 func EncodeToURLValues(v interface{}) (url.Values, error) {
-	return httpext.DefaultFormEncoder.Encode(v)
+	values, err := httpext.DefaultFormEncoder.Encode(v)
+	if err != nil {
+		return nil, err
+	}
+	for k, vals := range values {
+		if len(vals) == 0 {
+			delete(values, k)
+		}
+	}
+	return values, nil
 }
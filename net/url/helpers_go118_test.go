@@ -0,0 +1,23 @@
+//go:build go1.18
+// +build go1.18
+
+package urlext
+
+import (
+	"testing"
+
+	. "github.com/go-playground/assert/v2"
+	. "github.com/go-playground/pkg/v5/values/option"
+)
+
+func TestEncodeToURLValuesOption(t *testing.T) {
+	type Test struct {
+		Limit  Option[int]    `form:"limit"`
+		Cursor Option[string] `form:"cursor"`
+	}
+
+	values, err := EncodeToURLValues(Test{Limit: Some(0)})
+	Equal(t, err, nil)
+	Equal(t, len(values), 1)
+	Equal(t, values.Encode(), "limit=0")
+}
@@ -45,3 +45,26 @@ func StackLevel(skip int) (f Frame) {
 	f.Frame, _ = frames.Next()
 	return
 }
+
+// Frames returns all stack Frames of the calling goroutine skipping the number of supplied frames, with the first
+// Frame being the caller when skip is 0.
+func Frames(skip int) (stack []Frame) {
+	pc := make([]uintptr, 64)
+	for {
+		n := runtime.Callers(skip+2, pc)
+		if n < len(pc) {
+			pc = pc[:n]
+			break
+		}
+		pc = make([]uintptr, len(pc)*2)
+	}
+	frames := runtime.CallersFrames(pc)
+	for {
+		frame, more := frames.Next()
+		stack = append(stack, Frame{Frame: frame})
+		if !more {
+			break
+		}
+	}
+	return
+}
@@ -52,3 +52,20 @@ func TestStack(t *testing.T) {
 		})
 	}
 }
+
+func TestFrames(t *testing.T) {
+	frames := nestedFrames()
+	if len(frames) < 2 {
+		t.Fatalf("expected at least 2 frames, got %d", len(frames))
+	}
+	if frames[0].Function() != "nestedFrames" {
+		t.Errorf("expected function nestedFrames, got %s", frames[0].Function())
+	}
+	if frames[1].Function() != "TestFrames" {
+		t.Errorf("expected function TestFrames, got %s", frames[1].Function())
+	}
+}
+
+func nestedFrames() []Frame {
+	return Frames(0)
+}